@@ -5,24 +5,23 @@ import (
 	"crypto/sha256"
 	"io"
 	"io/ioutil"
-	"math/rand"
 	"testing"
 
-	"github.com/google/uuid"
 	. "github.com/smartystreets/goconvey/convey"
 
 	"github.com/beyondstorage/go-storage/v4/pairs"
-	"github.com/beyondstorage/go-storage/v4/pkg/randbytes"
 	"github.com/beyondstorage/go-storage/v4/types"
 )
 
 func TestAppender(t *testing.T, store types.Storager) {
+	setupRand(t)
+
 	Convey("Given a basic Storager", t, func() {
 		ap, ok := store.(types.Appender)
 		So(ok, ShouldBeTrue)
 
 		Convey("When CreateAppend", func() {
-			path := uuid.NewString()
+			path := randUUID()
 			o, err := ap.CreateAppend(path)
 
 			defer func() {
@@ -43,7 +42,7 @@ func TestAppender(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When CreateAppend with an existing object", func() {
-			path := uuid.NewString()
+			path := randUUID()
 			o, err := ap.CreateAppend(path)
 
 			defer func() {
@@ -57,8 +56,8 @@ func TestAppender(t *testing.T, store types.Storager) {
 				So(err, ShouldBeNil)
 			})
 
-			size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			r := io.LimitReader(randbytes.NewRand(), size)
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			r := io.LimitReader(randReader(), size)
 
 			_, err = ap.WriteAppend(o, r, size)
 			if err != nil {
@@ -87,7 +86,7 @@ func TestAppender(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When Delete", func() {
-			path := uuid.NewString()
+			path := randUUID()
 			_, err := ap.CreateAppend(path)
 			if err != nil {
 				t.Error(err)
@@ -105,7 +104,7 @@ func TestAppender(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When WriteAppend", func() {
-			path := uuid.NewString()
+			path := randUUID()
 			o, err := ap.CreateAppend(path)
 			if err != nil {
 				t.Error(err)
@@ -118,8 +117,8 @@ func TestAppender(t *testing.T, store types.Storager) {
 				}
 			}()
 
-			size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			content, _ := ioutil.ReadAll(io.LimitReader(randbytes.NewRand(), size))
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
 			r := bytes.NewReader(content)
 
 			n, err := ap.WriteAppend(o, r, size)
@@ -133,7 +132,7 @@ func TestAppender(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When CommitAppend", func() {
-			path := uuid.NewString()
+			path := randUUID()
 			o, err := ap.CreateAppend(path)
 			if err != nil {
 				t.Error(err)
@@ -146,8 +145,8 @@ func TestAppender(t *testing.T, store types.Storager) {
 				}
 			}()
 
-			size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			content, _ := ioutil.ReadAll(io.LimitReader(randbytes.NewRand(), size))
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
 
 			_, err = ap.WriteAppend(o, bytes.NewReader(content), size)
 			if err != nil {
@@ -175,5 +174,149 @@ func TestAppender(t *testing.T, store types.Storager) {
 				So(sha256.Sum256(buf.Bytes()), ShouldResemble, sha256.Sum256(bytes.Repeat(content, 2)))
 			})
 		})
+
+		Convey("When WriteAppend many times", func() {
+			path := randUUID()
+			o, err := ap.CreateAppend(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			var content []byte
+			var expected, offsets []int64
+			for i := randIntn(8) + 3; i > 0; i-- {
+				size := randInt63n(1024*1024) + 1 // Max append size is 1MB, as we append up to 10 times
+				c, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+
+				_, err = ap.WriteAppend(o, bytes.NewReader(c), size)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				content = append(content, c...)
+				expected = append(expected, int64(len(content)))
+				offsets = append(offsets, o.MustGetAppendOffset())
+			}
+
+			err = ap.CommitAppend(o)
+
+			Convey("CommitAppend error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The append offset should grow with every write", func() {
+				So(offsets, ShouldResemble, expected)
+			})
+
+			Convey("The size should be match", func() {
+				so, err := store.Stat(path)
+				So(err, ShouldBeNil)
+
+				osize, ok := so.GetContentLength()
+				So(ok, ShouldBeTrue)
+				So(osize, ShouldEqual, len(content))
+			})
+
+			Convey("The content should be match", func() {
+				var buf bytes.Buffer
+				n, err := store.Read(path, &buf)
+
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, len(content))
+				So(sha256.Sum256(buf.Bytes()), ShouldResemble, sha256.Sum256(content))
+			})
+		})
+
+		Convey("When Read before CommitAppend", func() {
+			path := randUUID()
+			o, err := ap.CreateAppend(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+
+			_, err = ap.WriteAppend(o, bytes.NewReader(content), size)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			_, err = store.Read(path, &buf)
+
+			Convey("The content should be match if readable", func() {
+				// Services differ on whether uncommitted content is readable, but
+				// it must not be partial or stale.
+				if err != nil {
+					t.Logf("%s doesn't allow reading before CommitAppend: %v", store, err)
+					return
+				}
+				So(sha256.Sum256(buf.Bytes()), ShouldResemble, sha256.Sum256(content))
+			})
+		})
+
+		Convey("When WriteAppend after CommitAppend", func() {
+			path := randUUID()
+			o, err := ap.CreateAppend(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+
+			_, err = ap.WriteAppend(o, bytes.NewReader(content), size)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = ap.CommitAppend(o)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, firstErr := ap.WriteAppend(o, bytes.NewReader(content), size)
+			_, secondErr := ap.WriteAppend(o, bytes.NewReader(content), size)
+
+			Convey("The behavior should be consistent", func() {
+				So(firstErr == nil, ShouldEqual, secondErr == nil)
+			})
+
+			Convey("The content should be match the behavior", func() {
+				var buf bytes.Buffer
+				_, err := store.Read(path, &buf)
+				So(err, ShouldBeNil)
+
+				// The object is reopened if WriteAppend succeeds, otherwise it
+				// must be left untouched.
+				expected := content
+				if firstErr == nil {
+					expected = bytes.Repeat(content, 3)
+				}
+				So(sha256.Sum256(buf.Bytes()), ShouldResemble, sha256.Sum256(expected))
+			})
+		})
 	})
 }
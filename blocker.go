@@ -0,0 +1,279 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+func TestBlocker(t *testing.T, store types.Storager) {
+	setupRand(t)
+
+	Convey("Given a basic Storager", t, func() {
+		b, ok := store.(types.Blocker)
+		So(ok, ShouldBeTrue)
+
+		Convey("When CreateBlock", func() {
+			path := randUUID()
+			o, err := b.CreateBlock(path)
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			Convey("The first returned error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			o, err = b.CreateBlock(path)
+
+			Convey("The second returned error also should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The Object Path should equal to the input path", func() {
+				So(o.Path, ShouldEqual, path)
+			})
+
+			Convey("The Object Mode should be block", func() {
+				// Block object's mode must be Block.
+				So(o.Mode.IsBlock(), ShouldBeTrue)
+			})
+		})
+
+		Convey("When Delete", func() {
+			path := randUUID()
+			_, err := b.CreateBlock(path)
+			if err != nil {
+				t.Error(err)
+			}
+
+			err = store.Delete(path)
+			Convey("The first returned error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			err = store.Delete(path)
+			Convey("The second returned error also should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When WriteBlock", func() {
+			path := randUUID()
+			o, err := b.CreateBlock(path)
+			if err != nil {
+				t.Error(err)
+			}
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			r := io.LimitReader(randReader(), size)
+
+			n, err := b.WriteBlock(o, r, size, randUUID())
+
+			Convey("WriteBlock error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("WriteBlock size should be equal to n", func() {
+				So(n, ShouldEqual, size)
+			})
+		})
+
+		Convey("When ListBlock", func() {
+			path := randUUID()
+			o, err := b.CreateBlock(path)
+			if err != nil {
+				t.Error(err)
+			}
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			r := io.LimitReader(randReader(), size)
+			// Block id must be the same length for all blocks in an object, uuid satisfies it.
+			bid := randUUID()
+
+			_, err = b.WriteBlock(o, r, size, bid)
+			if err != nil {
+				t.Error(err)
+			}
+
+			it, err := b.ListBlock(o)
+
+			Convey("ListBlock error should be nil", func() {
+				So(err, ShouldBeNil)
+				So(it, ShouldNotBeNil)
+			})
+
+			bl, err := it.Next()
+			Convey("Next error should be nil", func() {
+				So(err, ShouldBeNil)
+				So(bl, ShouldNotBeNil)
+			})
+			Convey("The block id and size should be match", func() {
+				So(bl.ID, ShouldEqual, bid)
+				So(bl.Size, ShouldEqual, size)
+			})
+		})
+
+		Convey("When List with block type", func() {
+			path := randUUID()
+			o, err := b.CreateBlock(path)
+			if err != nil {
+				t.Error(err)
+			}
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			r := io.LimitReader(randReader(), size)
+
+			// The block is written but not combined, so it's still uncommitted.
+			_, err = b.WriteBlock(o, r, size, randUUID())
+			if err != nil {
+				t.Error(err)
+			}
+
+			it, err := store.List("", pairs.WithListMode(types.ListModeBlock))
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("The iterator should not be nil", func() {
+				So(it, ShouldNotBeNil)
+			})
+
+			mo, err := it.Next()
+			Convey("Next error should be nil", func() {
+				So(err, ShouldBeNil)
+				So(mo, ShouldNotBeNil)
+			})
+			Convey("The path and mode should be match", func() {
+				So(mo.Path, ShouldEqual, path)
+				So(mo.Mode.IsBlock(), ShouldBeTrue)
+			})
+		})
+
+		Convey("When CombineBlock", func() {
+			path := randUUID()
+			o, err := b.CreateBlock(path)
+			if err != nil {
+				t.Error(err)
+			}
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			// Write blocks in order and combine them in reverse order, so that
+			// we can make sure the combined content follows the input block ids.
+			var bids []string
+			var contents [][]byte
+			for i := 0; i < 3; i++ {
+				size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+				content, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+				bid := randUUID()
+
+				_, err := b.WriteBlock(o, bytes.NewReader(content), size, bid)
+				if err != nil {
+					t.Error(err)
+				}
+
+				bids = append([]string{bid}, bids...)
+				contents = append([][]byte{content}, contents...)
+			}
+
+			err = b.CombineBlock(o, bids)
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The object should be readable after combine", func() {
+				ro, err := store.Stat(path)
+
+				So(err, ShouldBeNil)
+				So(ro.Mode.IsRead(), ShouldBeTrue)
+
+				osize, ok := ro.GetContentLength()
+				So(ok, ShouldBeTrue)
+				So(osize, ShouldEqual, int64(len(bytes.Join(contents, nil))))
+			})
+
+			Convey("The content should be match the combined order", func() {
+				var buf bytes.Buffer
+				_, err := store.Read(path, &buf)
+
+				So(err, ShouldBeNil)
+				So(sha256.Sum256(buf.Bytes()), ShouldResemble, sha256.Sum256(bytes.Join(contents, nil)))
+			})
+		})
+
+		Convey("When Delete a combined block object", func() {
+			path := randUUID()
+			o, err := b.CreateBlock(path)
+			if err != nil {
+				t.Error(err)
+			}
+
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			r := io.LimitReader(randReader(), size)
+			bid := randUUID()
+
+			_, err = b.WriteBlock(o, r, size, bid)
+			if err != nil {
+				t.Error(err)
+			}
+
+			err = b.CombineBlock(o, []string{bid})
+			if err != nil {
+				t.Error(err)
+			}
+
+			err = store.Delete(path)
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Stat should get nil Object and ObjectNotFound error", func() {
+				o, err := store.Stat(path)
+
+				So(errors.Is(err, services.ErrObjectNotExist), ShouldBeTrue)
+				So(o, ShouldBeNil)
+			})
+		})
+	})
+}
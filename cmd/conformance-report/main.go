@@ -0,0 +1,94 @@
+// Command conformance-report converts `go test -v` outputs of services into JSON and
+// JUnit XML reports, and a compatibility matrix across all services.
+//
+// Usage:
+//
+//	GOCONVEY_REPORTER=json go test -v ./... > memory.log
+//	conformance-report -out reports memory=memory.log s3=s3.log
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/beyondstorage/go-integration-test/v4/report"
+)
+
+func main() {
+	out := flag.String("out", ".", "the dir to write reports")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-out dir] <service>=<go test output>...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	err := os.MkdirAll(*out, 0755)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var reports []*report.Report
+	for _, arg := range flag.Args() {
+		idx := strings.Index(arg, "=")
+		if idx <= 0 {
+			log.Fatalf("invalid argument %q, expected <service>=<go test output>", arg)
+		}
+		service, path := arg[:idx], arg[idx+1:]
+
+		r, err := parse(service, path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		reports = append(reports, r)
+
+		err = write(filepath.Join(*out, service+".json"), r.WriteJSON)
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = write(filepath.Join(*out, service+".xml"), r.WriteJUnit)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	err = write(filepath.Join(*out, "matrix.md"), func(w io.Writer) error {
+		return report.WriteMatrix(w, reports...)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func parse(service, path string) (*report.Report, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return report.Parse(service, f)
+}
+
+func write(path string, fn func(w io.Writer) error) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	return fn(f)
+}
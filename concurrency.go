@@ -0,0 +1,319 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// concurrency is the number of goroutines used in TestStoragerConcurrency.
+const concurrency = 16
+
+// errorList collects errors from multiple goroutines, as So can't be used
+// outside of the goroutine running Convey.
+type errorList struct {
+	lock sync.Mutex
+	errs []error
+}
+
+func (l *errorList) Add(format string, args ...interface{}) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.errs = append(l.errs, fmt.Errorf(format, args...))
+}
+
+func (l *errorList) Errors() []error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.errs
+}
+
+// randContents returns n random contents, they are generated before starting goroutines
+// so that they are reproducible with the same seed.
+func randContents(n int, maxSize int64) [][]byte {
+	contents := make([][]byte, n)
+	for i := range contents {
+		contents[i], _ = ioutil.ReadAll(io.LimitReader(randReader(), randInt63n(maxSize)))
+	}
+	return contents
+}
+
+// TestStoragerConcurrency runs operations from multiple goroutines at the same time,
+// it's designed to be run with `-race` to detect races in services.
+func TestStoragerConcurrency(t *testing.T, store types.Storager) {
+	setupRand(t)
+
+	Convey("Given a basic Storager", t, func() {
+		So(store, ShouldNotBeNil)
+
+		Convey("When Write, Read, Stat and Delete on disjoint paths concurrently", func() {
+			contents := randContents(concurrency, 1024*1024)
+			paths := make([]string, concurrency)
+			for i := range paths {
+				paths[i] = randUUID()
+			}
+
+			var errs errorList
+			var wg sync.WaitGroup
+			for i := 0; i < concurrency; i++ {
+				wg.Add(1)
+				go func(path string, content []byte) {
+					defer wg.Done()
+
+					size := int64(len(content))
+					_, err := store.Write(path, bytes.NewReader(content), size)
+					if err != nil {
+						errs.Add("write %s: %w", path, err)
+						return
+					}
+
+					o, err := store.Stat(path)
+					if err != nil {
+						errs.Add("stat %s: %w", path, err)
+						return
+					}
+					if osize, ok := o.GetContentLength(); !ok || osize != size {
+						errs.Add("stat %s: content length %d, expected %d", path, osize, size)
+					}
+
+					var buf bytes.Buffer
+					_, err = store.Read(path, &buf)
+					if err != nil {
+						errs.Add("read %s: %w", path, err)
+						return
+					}
+					if sha256.Sum256(buf.Bytes()) != sha256.Sum256(content) {
+						errs.Add("read %s: content mismatch", path)
+					}
+
+					err = store.Delete(path)
+					if err != nil {
+						errs.Add("delete %s: %w", path, err)
+						return
+					}
+
+					_, err = store.Stat(path)
+					if !errors.Is(err, services.ErrObjectNotExist) {
+						errs.Add("stat %s after delete: %v, expected ErrObjectNotExist", path, err)
+					}
+				}(paths[i], contents[i])
+			}
+			wg.Wait()
+
+			Convey("All operations should succeed", func() {
+				So(errs.Errors(), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When Write and Read on the same path concurrently", func() {
+			path := randUUID()
+			contents := randContents(concurrency, 1024*1024)
+
+			sums := make(map[[sha256.Size]byte]bool, len(contents))
+			for _, content := range contents {
+				sums[sha256.Sum256(content)] = true
+			}
+
+			// Write the first content before all goroutines, so that every read
+			// could observe a complete object.
+			_, err := store.Write(path, bytes.NewReader(contents[0]), int64(len(contents[0])))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			var errs errorList
+			var wg sync.WaitGroup
+			for i := 1; i < concurrency; i++ {
+				wg.Add(2)
+				go func(content []byte) {
+					defer wg.Done()
+
+					_, err := store.Write(path, bytes.NewReader(content), int64(len(content)))
+					if err != nil {
+						errs.Add("write %s: %w", path, err)
+					}
+				}(contents[i])
+				go func() {
+					defer wg.Done()
+
+					var buf bytes.Buffer
+					_, err := store.Read(path, &buf)
+					if err != nil {
+						errs.Add("read %s: %w", path, err)
+						return
+					}
+					if !sums[sha256.Sum256(buf.Bytes())] {
+						errs.Add("read %s: content is not written by any writer", path)
+					}
+				}()
+			}
+			wg.Wait()
+
+			Convey("All operations should succeed", func() {
+				So(errs.Errors(), ShouldBeEmpty)
+			})
+
+			Convey("The content should be written by one of the writers", func() {
+				var buf bytes.Buffer
+				n, err := store.Read(path, &buf)
+
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, buf.Len())
+				So(sums[sha256.Sum256(buf.Bytes())], ShouldBeTrue)
+			})
+
+			Convey("The size should match the content", func() {
+				var buf bytes.Buffer
+				_, err := store.Read(path, &buf)
+				So(err, ShouldBeNil)
+
+				o, err := store.Stat(path)
+				So(err, ShouldBeNil)
+
+				osize, ok := o.GetContentLength()
+				So(ok, ShouldBeTrue)
+				So(osize, ShouldEqual, buf.Len())
+			})
+		})
+
+		Convey("When Stat and Delete on the same path concurrently", func() {
+			path := randUUID()
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+
+			_, err := store.Write(path, io.LimitReader(randReader(), size), size)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var errs errorList
+			var wg sync.WaitGroup
+			for i := 0; i < concurrency; i++ {
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+
+					// Delete is idempotent, so all of them should succeed.
+					err := store.Delete(path)
+					if err != nil {
+						errs.Add("delete %s: %w", path, err)
+					}
+				}()
+				go func() {
+					defer wg.Done()
+
+					o, err := store.Stat(path)
+					if err != nil {
+						if !errors.Is(err, services.ErrObjectNotExist) {
+							errs.Add("stat %s: %w", path, err)
+						}
+						return
+					}
+					if osize, ok := o.GetContentLength(); !ok || osize != size {
+						errs.Add("stat %s: content length %d, expected %d", path, osize, size)
+					}
+				}()
+			}
+			wg.Wait()
+
+			Convey("All operations should succeed", func() {
+				So(errs.Errors(), ShouldBeEmpty)
+			})
+
+			Convey("Stat should get nil Object and ObjectNotFound error", func() {
+				o, err := store.Stat(path)
+
+				So(errors.Is(err, services.ErrObjectNotExist), ShouldBeTrue)
+				So(o, ShouldBeNil)
+			})
+		})
+
+		Convey("When List the same dir concurrently", func() {
+			dir := randUUID()
+			num := randIntn(16) + 1
+
+			expected := make(map[string]int64, num)
+			for i := 0; i < num; i++ {
+				path := dir + "/" + randUUID()
+				size := randInt63n(1024)
+
+				_, err := store.Write(path, io.LimitReader(randReader(), size), size)
+				if err != nil {
+					t.Fatal(err)
+				}
+				expected[path] = size
+			}
+
+			defer func() {
+				for path := range expected {
+					err := store.Delete(path)
+					if err != nil {
+						t.Error(err)
+					}
+				}
+			}()
+
+			var errs errorList
+			var wg sync.WaitGroup
+			for i := 0; i < concurrency; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					it, err := store.List(dir+"/", pairs.WithListMode(types.ListModePrefix))
+					if err != nil {
+						errs.Add("list %s: %w", dir, err)
+						return
+					}
+
+					actual := make(map[string]int64)
+					for {
+						o, err := it.Next()
+						if err == types.IterateDone {
+							break
+						}
+						if err != nil {
+							errs.Add("list %s: %w", dir, err)
+							return
+						}
+						actual[o.Path], _ = o.GetContentLength()
+					}
+
+					if len(actual) != len(expected) {
+						errs.Add("list %s: got %d objects, expected %d", dir, len(actual), len(expected))
+						return
+					}
+					for path, size := range expected {
+						if osize, ok := actual[path]; !ok || osize != size {
+							errs.Add("list %s: object %s is missing or has wrong size", dir, path)
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			Convey("All iterators should list all objects", func() {
+				So(errs.Errors(), ShouldBeEmpty)
+			})
+		})
+	})
+}
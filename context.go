@@ -0,0 +1,407 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// promptly is the max duration an operation could take after its context is done.
+const promptly = 10 * time.Second
+
+// slowReader is a reader which sleeps before every read, so that operations on it
+// will last long enough to be interrupted.
+type slowReader struct {
+	r     io.Reader
+	delay time.Duration
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	time.Sleep(r.delay)
+	// Only read 1KB once to make sure the reader is slow enough.
+	if len(p) > 1024 {
+		p = p[:1024]
+	}
+	return r.r.Read(p)
+}
+
+func TestContextCancellation(t *testing.T, store types.Storager) {
+	setupRand(t)
+
+	Convey("Given a basic Storager", t, func() {
+		So(store, ShouldNotBeNil)
+
+		Convey("When WriteWithContext with a canceled context", func() {
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			r := io.LimitReader(randReader(), size)
+			path := randUUID()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err := store.WriteWithContext(ctx, path, r, size)
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			Convey("The error should be context.Canceled", func() {
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+			})
+
+			Convey("Stat should get nil Object and ObjectNotFound error", func() {
+				o, err := store.Stat(path)
+
+				So(errors.Is(err, services.ErrObjectNotExist), ShouldBeTrue)
+				So(o, ShouldBeNil)
+			})
+		})
+
+		Convey("When WriteWithContext and cancel the context during writing", func() {
+			// Make sure the content is larger than one chunk.
+			size := randInt63n(3*1024*1024) + 1024*1024
+			r := io.LimitReader(randReader(), size)
+			path := randUUID()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			_, err := store.WriteWithContext(ctx, path, r, size, pairs.WithIoCallback(func(bs []byte) {
+				cancel()
+			}))
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			Convey("The error should be context.Canceled", func() {
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+			})
+
+			Convey("Stat should get nil Object and ObjectNotFound error", func() {
+				o, err := store.Stat(path)
+
+				So(errors.Is(err, services.ErrObjectNotExist), ShouldBeTrue)
+				So(o, ShouldBeNil)
+			})
+		})
+
+		Convey("When WriteWithContext with a deadline exceeded during writing", func() {
+			size := randInt63n(3*1024*1024) + 1024*1024
+			r := &slowReader{r: io.LimitReader(randReader(), size), delay: 10 * time.Millisecond}
+			path := randUUID()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			_, err := store.WriteWithContext(ctx, path, r, size)
+			elapsed := time.Since(start)
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			Convey("The error should be context.DeadlineExceeded", func() {
+				So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+			})
+
+			Convey("The write should return promptly", func() {
+				So(elapsed, ShouldBeLessThan, promptly)
+			})
+
+			Convey("Stat should get nil Object and ObjectNotFound error", func() {
+				o, err := store.Stat(path)
+
+				So(errors.Is(err, services.ErrObjectNotExist), ShouldBeTrue)
+				So(o, ShouldBeNil)
+			})
+		})
+
+		Convey("When operate on an existing file with a canceled context", func() {
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+			path := randUUID()
+
+			_, err := store.Write(path, bytes.NewReader(content), size)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			Convey("ReadWithContext should return context.Canceled", func() {
+				var buf bytes.Buffer
+				_, err := store.ReadWithContext(ctx, path, &buf)
+
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+			})
+
+			Convey("StatWithContext should return context.Canceled", func() {
+				_, err := store.StatWithContext(ctx, path)
+
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+			})
+
+			Convey("DeleteWithContext should return context.Canceled", func() {
+				err := store.DeleteWithContext(ctx, path)
+
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+			})
+		})
+
+		Convey("When ReadWithContext and cancel the context during reading", func() {
+			size := randInt63n(3*1024*1024) + 1024*1024
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+			path := randUUID()
+
+			_, err := store.Write(path, bytes.NewReader(content), size)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var buf bytes.Buffer
+			n, err := store.ReadWithContext(ctx, path, &buf, pairs.WithIoCallback(func(bs []byte) {
+				cancel()
+			}))
+
+			Convey("The error should be context.Canceled", func() {
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+			})
+
+			Convey("The content should not be read completely", func() {
+				So(n, ShouldBeLessThan, size)
+			})
+		})
+
+		Convey("When operate after the context canceled", func() {
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+			path := randUUID()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			_, err := store.WriteWithContext(ctx, path, bytes.NewReader(content), size)
+			cancel()
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			Convey("The write error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The file should be read with a new context", func() {
+				var buf bytes.Buffer
+				n, err := store.ReadWithContext(context.Background(), path, &buf)
+
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, size)
+				So(buf.Bytes(), ShouldResemble, content)
+			})
+		})
+
+		Convey("When ListWithContext with a canceled context", func() {
+			dir := randUUID()
+			num := randIntn(8) + 2
+
+			paths := make([]string, 0, num)
+			for i := 0; i < num; i++ {
+				path := dir + "/" + randUUID()
+				size := randInt63n(1024)
+
+				_, err := store.Write(path, io.LimitReader(randReader(), size), size)
+				if err != nil {
+					t.Fatal(err)
+				}
+				paths = append(paths, path)
+			}
+
+			defer func() {
+				for _, path := range paths {
+					err := store.Delete(path)
+					if err != nil {
+						t.Error(err)
+					}
+				}
+			}()
+
+			Convey("The iterator should not return any object", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				it, err := store.ListWithContext(ctx, dir+"/", pairs.WithListMode(types.ListModePrefix))
+				if err == nil {
+					var o *types.Object
+					o, err = it.Next()
+					So(o, ShouldBeNil)
+				}
+
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+			})
+		})
+
+		if ap, ok := store.(types.Appender); ok {
+			Convey("When CreateAppendWithContext with a canceled context", func() {
+				path := randUUID()
+
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				_, err := ap.CreateAppendWithContext(ctx, path)
+
+				Convey("The error should be context.Canceled", func() {
+					So(errors.Is(err, context.Canceled), ShouldBeTrue)
+				})
+
+				Convey("Stat should get nil Object and ObjectNotFound error", func() {
+					o, err := store.Stat(path)
+
+					So(errors.Is(err, services.ErrObjectNotExist), ShouldBeTrue)
+					So(o, ShouldBeNil)
+				})
+			})
+
+			Convey("When WriteAppendWithContext and cancel the context during writing", func() {
+				path := randUUID()
+				o, err := ap.CreateAppend(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				defer func() {
+					err := store.Delete(path)
+					if err != nil {
+						t.Error(err)
+					}
+				}()
+
+				size := randInt63n(3*1024*1024) + 1024*1024
+				r := io.LimitReader(randReader(), size)
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				_, err = ap.WriteAppendWithContext(ctx, o, r, size, pairs.WithIoCallback(func(bs []byte) {
+					cancel()
+				}))
+
+				Convey("The error should be context.Canceled", func() {
+					So(errors.Is(err, context.Canceled), ShouldBeTrue)
+				})
+
+				Convey("CommitAppendWithContext with a canceled context should return context.Canceled", func() {
+					err := ap.CommitAppendWithContext(ctx, o)
+
+					So(errors.Is(err, context.Canceled), ShouldBeTrue)
+				})
+			})
+		}
+
+		if m, ok := store.(types.Multiparter); ok {
+			Convey("When CreateMultipartWithContext with a canceled context", func() {
+				path := randUUID()
+
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				_, err := m.CreateMultipartWithContext(ctx, path)
+
+				Convey("The error should be context.Canceled", func() {
+					So(errors.Is(err, context.Canceled), ShouldBeTrue)
+				})
+			})
+
+			Convey("When operate on a multipart with a canceled context", func() {
+				path := randUUID()
+				o, err := m.CreateMultipart(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				defer func() {
+					err := store.Delete(path, pairs.WithMultipartID(o.MustGetMultipartID()))
+					if err != nil {
+						t.Error(err)
+					}
+				}()
+
+				size := randInt63n(3*1024*1024) + 1024*1024
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				_, _, err = m.WriteMultipartWithContext(ctx, o, io.LimitReader(randReader(), size), size, 0,
+					pairs.WithIoCallback(func(bs []byte) {
+						cancel()
+					}))
+
+				Convey("WriteMultipartWithContext should return context.Canceled", func() {
+					So(errors.Is(err, context.Canceled), ShouldBeTrue)
+				})
+
+				Convey("ListMultipartWithContext should return context.Canceled", func() {
+					it, err := m.ListMultipartWithContext(ctx, o)
+					if err == nil {
+						_, err = it.Next()
+					}
+
+					So(errors.Is(err, context.Canceled), ShouldBeTrue)
+				})
+
+				Convey("CompleteMultipartWithContext should return context.Canceled", func() {
+					err := m.CompleteMultipartWithContext(ctx, o, []*types.Part{{Index: 0, Size: size}})
+
+					So(errors.Is(err, context.Canceled), ShouldBeTrue)
+				})
+
+				Convey("Stat should get nil Object and ObjectNotFound error", func() {
+					o, err := store.Stat(path)
+
+					So(errors.Is(err, services.ErrObjectNotExist), ShouldBeTrue)
+					So(o, ShouldBeNil)
+				})
+			})
+		}
+	})
+}
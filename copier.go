@@ -6,27 +6,26 @@ import (
 	"errors"
 	"io"
 	"io/ioutil"
-	"math/rand"
 	"testing"
 
-	"github.com/google/uuid"
 	. "github.com/smartystreets/goconvey/convey"
 
 	"github.com/beyondstorage/go-storage/v4/pairs"
-	"github.com/beyondstorage/go-storage/v4/pkg/randbytes"
 	"github.com/beyondstorage/go-storage/v4/services"
 	"github.com/beyondstorage/go-storage/v4/types"
 )
 
 func TestCopier(t *testing.T, store types.Storager) {
+	setupRand(t)
+
 	Convey("Given a basic Storager", t, func() {
 		c, ok := store.(types.Copier)
 		So(ok, ShouldBeTrue)
 
 		Convey("When Copy a file", func() {
-			size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			content, _ := ioutil.ReadAll(io.LimitReader(randbytes.NewRand(), size))
-			src := uuid.New().String()
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+			src := randUUID()
 
 			_, err := store.Write(src, bytes.NewReader(content), size)
 			if err != nil {
@@ -40,7 +39,7 @@ func TestCopier(t *testing.T, store types.Storager) {
 				}
 			}()
 
-			dst := uuid.New().String()
+			dst := randUUID()
 			err = c.Copy(src, dst)
 
 			defer func() {
@@ -71,9 +70,9 @@ func TestCopier(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When Copy to an existing file", func() {
-			srcSize := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			content, _ := ioutil.ReadAll(io.LimitReader(randbytes.NewRand(), srcSize))
-			src := uuid.New().String()
+			srcSize := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), srcSize))
+			src := randUUID()
 
 			_, err := store.Write(src, bytes.NewReader(content), srcSize)
 			if err != nil {
@@ -87,9 +86,9 @@ func TestCopier(t *testing.T, store types.Storager) {
 				}
 			}()
 
-			dstSize := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			r := io.LimitReader(randbytes.NewRand(), dstSize)
-			dst := uuid.New().String()
+			dstSize := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			r := io.LimitReader(randReader(), dstSize)
+			dst := randUUID()
 
 			_, err = store.Write(dst, r, dstSize)
 			if err != nil {
@@ -127,6 +126,8 @@ func TestCopier(t *testing.T, store types.Storager) {
 }
 
 func TestCopierWithDir(t *testing.T, store types.Storager) {
+	setupRand(t)
+
 	Convey("Given a basic Storager", t, func() {
 		c, ok := store.(types.Copier)
 		So(ok, ShouldBeTrue)
@@ -134,9 +135,9 @@ func TestCopierWithDir(t *testing.T, store types.Storager) {
 		d := store.(types.Direr)
 
 		Convey("When Copy to an existing dir", func() {
-			srcSize := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			r := io.LimitReader(randbytes.NewRand(), srcSize)
-			src := uuid.New().String()
+			srcSize := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			r := io.LimitReader(randReader(), srcSize)
+			src := randUUID()
 
 			_, err := store.Write(src, r, srcSize)
 			if err != nil {
@@ -150,7 +151,7 @@ func TestCopierWithDir(t *testing.T, store types.Storager) {
 				}
 			}()
 
-			dst := uuid.New().String()
+			dst := randUUID()
 			_, err = d.CreateDir(dst)
 			if err != nil {
 				t.Fatal(err)
@@ -172,6 +173,8 @@ func TestCopierWithDir(t *testing.T, store types.Storager) {
 }
 
 func TestCopierWithVirtualDir(t *testing.T, store types.Storager) {
+	setupRand(t)
+
 	Convey("Given a basic Storager", t, func() {
 		c, ok := store.(types.Copier)
 		So(ok, ShouldBeTrue)
@@ -179,9 +182,9 @@ func TestCopierWithVirtualDir(t *testing.T, store types.Storager) {
 		d := store.(types.Direr)
 
 		Convey("When Copy to an existing dir", func() {
-			srcSize := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			r := io.LimitReader(randbytes.NewRand(), srcSize)
-			src := uuid.New().String()
+			srcSize := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			r := io.LimitReader(randReader(), srcSize)
+			src := randUUID()
 
 			_, err := store.Write(src, r, srcSize)
 			if err != nil {
@@ -195,7 +198,7 @@ func TestCopierWithVirtualDir(t *testing.T, store types.Storager) {
 				}
 			}()
 
-			dst := uuid.New().String()
+			dst := randUUID()
 			_, err = d.CreateDir(dst)
 			if err != nil {
 				t.Fatal(err)
@@ -243,3 +246,18 @@ func TestCopierWithVirtualDir(t *testing.T, store types.Storager) {
 		})
 	})
 }
+
+// TestCopierWithProfile will run TestCopier and the dir tests which match
+// the dir behavior in p if store is also a Direr.
+func TestCopierWithProfile(t *testing.T, store types.Storager, p Profile) {
+	TestCopier(t, store)
+
+	if _, ok := store.(types.Direr); !ok {
+		return
+	}
+	if p.VirtualDir {
+		TestCopierWithVirtualDir(t, store)
+	} else {
+		TestCopierWithDir(t, store)
+	}
+}
@@ -3,7 +3,6 @@ package tests
 import (
 	"testing"
 
-	"github.com/google/uuid"
 	. "github.com/smartystreets/goconvey/convey"
 
 	"github.com/beyondstorage/go-storage/v4/pairs"
@@ -11,12 +10,14 @@ import (
 )
 
 func TestDirer(t *testing.T, store types.Storager) {
+	setupRand(t)
+
 	Convey("Given a basic Storager", t, func() {
 		d, ok := store.(types.Direr)
 		So(ok, ShouldBeTrue)
 
 		Convey("When CreateDir", func() {
-			path := uuid.New().String()
+			path := randUUID()
 			o, err := d.CreateDir(path)
 
 			defer func() {
@@ -46,7 +47,7 @@ func TestDirer(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When Create with ModeDir", func() {
-			path := uuid.New().String()
+			path := randUUID()
 			o := store.Create(path, pairs.WithObjectMode(types.ModeDir))
 
 			defer func() {
@@ -67,7 +68,7 @@ func TestDirer(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When Stat with ModeDir", func() {
-			path := uuid.New().String()
+			path := randUUID()
 			_, err := d.CreateDir(path)
 			if err != nil {
 				t.Error(err)
@@ -97,7 +98,7 @@ func TestDirer(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When Delete with ModeDir", func() {
-			path := uuid.New().String()
+			path := randUUID()
 			_, err := d.CreateDir(path)
 			if err != nil {
 				t.Error(err)
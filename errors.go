@@ -0,0 +1,154 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// unsupportedPairKey is the key of a pair which is not supported by any service.
+const unsupportedPairKey = "storage_integration_test_unsupported"
+
+// shouldBeStorageError asserts that actual is or wraps a services.StorageError with
+// the expected op and path.
+func shouldBeStorageError(actual interface{}, expected ...interface{}) string {
+	op, path := expected[0].(string), expected[1].(string)
+
+	err, _ := actual.(error)
+	var se services.StorageError
+	if !errors.As(err, &se) {
+		return fmt.Sprintf("Expected a services.StorageError, but got %T: %v", actual, actual)
+	}
+	if se.Op != op {
+		return fmt.Sprintf("Expected op %q, but got %q: %v", op, se.Op, se)
+	}
+	if len(se.Path) == 0 || se.Path[0] != path {
+		return fmt.Sprintf("Expected path %q, but got %q: %v", path, se.Path, se)
+	}
+	return ""
+}
+
+func TestErrors(t *testing.T, store types.Storager) {
+	TestErrorsWithProfile(t, store, Profile{})
+}
+
+// TestErrorsWithProfile provokes the documented failures, and checks that they wrap
+// the services error codes and a StorageError with the operation and path.
+//
+// go-storage doesn't have an ErrPairUnsupported error code, unsupported pairs are
+// reported by PairUnsupportedError which wraps ErrCapabilityInsufficient instead.
+func TestErrorsWithProfile(t *testing.T, store types.Storager, p Profile) {
+	setupRand(t)
+
+	Convey("Given a basic Storager", t, func() {
+		So(store, ShouldNotBeNil)
+
+		Convey("When Read a file which doesn't exist", func() {
+			path := randUUID()
+			_, err := store.Read(path, ioutil.Discard)
+
+			Convey("The error should be ErrObjectNotExist", func() {
+				So(errors.Is(err, services.ErrObjectNotExist), ShouldBeTrue)
+			})
+
+			Convey("The error should be a StorageError with op and path", func() {
+				So(err, shouldBeStorageError, "read", path)
+			})
+		})
+
+		Convey("When Stat a file which doesn't exist", func() {
+			path := randUUID()
+			_, err := store.Stat(path)
+
+			Convey("The error should be ErrObjectNotExist", func() {
+				So(errors.Is(err, services.ErrObjectNotExist), ShouldBeTrue)
+			})
+
+			Convey("The error should be a StorageError with op and path", func() {
+				So(err, shouldBeStorageError, "stat", path)
+			})
+		})
+
+		Convey("When List with an invalid list mode", func() {
+			path := randUUID() + "/"
+			mode := types.ListMode(0)
+
+			it, err := store.List(path, pairs.WithListMode(mode))
+			// Services could check the list mode while listing the first page.
+			if err == nil {
+				_, err = it.Next()
+			}
+
+			Convey("The error should be ErrListModeInvalid", func() {
+				So(errors.Is(err, services.ErrListModeInvalid), ShouldBeTrue)
+
+				var le services.ListModeInvalidError
+				So(errors.As(err, &le), ShouldBeTrue)
+				So(le.Actual, ShouldEqual, mode)
+			})
+
+			Convey("The error should be a StorageError with op and path", func() {
+				So(err, shouldBeStorageError, "list", path)
+			})
+		})
+
+		Convey("When Stat with an unsupported pair", func() {
+			path := randUUID()
+			_, err := store.Stat(path, types.Pair{Key: unsupportedPairKey, Value: randUUID()})
+
+			// Services with loose_pair enabled will ignore the pair.
+			if errors.Is(err, services.ErrObjectNotExist) {
+				t.Logf("%s ignores unsupported pairs", store)
+				return
+			}
+
+			Convey("The error should be ErrCapabilityInsufficient", func() {
+				So(errors.Is(err, services.ErrCapabilityInsufficient), ShouldBeTrue)
+
+				var pe services.PairUnsupportedError
+				So(errors.As(err, &pe), ShouldBeTrue)
+				So(pe.Pair.Key, ShouldEqual, unsupportedPairKey)
+			})
+
+			Convey("The error should be a StorageError with op and path", func() {
+				So(err, shouldBeStorageError, "stat", path)
+			})
+		})
+
+		if p.PermissionDeniedPath == "" {
+			return
+		}
+		path := p.PermissionDeniedPath
+
+		Convey("When Read a file without permission", func() {
+			_, err := store.Read(path, ioutil.Discard)
+
+			Convey("The error should be ErrPermissionDenied", func() {
+				So(errors.Is(err, services.ErrPermissionDenied), ShouldBeTrue)
+			})
+
+			Convey("The error should be a StorageError with op and path", func() {
+				So(err, shouldBeStorageError, "read", path)
+			})
+		})
+
+		Convey("When Stat a file without permission", func() {
+			_, err := store.Stat(path)
+
+			Convey("The error should be ErrPermissionDenied", func() {
+				So(errors.Is(err, services.ErrPermissionDenied), ShouldBeTrue)
+			})
+
+			Convey("The error should be a StorageError with op and path", func() {
+				So(err, shouldBeStorageError, "stat", path)
+			})
+		})
+	})
+}
@@ -0,0 +1,217 @@
+// Package fault provides a Storager wrapper which injects configurable failures,
+// so that we can check how services behave while things go wrong.
+package fault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// ErrInjected is the default error returned by injected failures.
+var ErrInjected = errors.New("fault injected")
+
+// Config is the faults to inject, the zero value injects nothing.
+type Config struct {
+	// FailOnCall makes the Nth (starts from 1) call on the Storager fail with Err, 0 means disabled.
+	FailOnCall int64
+	// Err is the error returned by injected failures, ErrInjected will be used if nil.
+	Err error
+	// Latency will be added before every call.
+	Latency time.Duration
+
+	// ShortRead makes the writer passed to Read fail with Err after ShortRead bytes written,
+	// like a connection closed in the middle of the response, 0 means disabled.
+	ShortRead int64
+	// TruncateWrite makes the reader passed to Write return io.EOF after TruncateWrite bytes
+	// no matter what the size is, 0 means disabled.
+	TruncateWrite int64
+	// ReaderErrorAfter makes the reader passed to Write return Err after ReaderErrorAfter bytes,
+	// 0 means disabled.
+	ReaderErrorAfter int64
+}
+
+// Storage is a Storager wrapper which injects faults into the underlying Storager.
+type Storage struct {
+	types.Storager
+
+	cfg   Config
+	calls int64
+}
+
+// New will wrap store with faults in cfg.
+func New(store types.Storager, cfg Config) *Storage {
+	if cfg.Err == nil {
+		cfg.Err = ErrInjected
+	}
+	return &Storage{Storager: store, cfg: cfg}
+}
+
+// String implements Storager.String
+func (s *Storage) String() string {
+	return fmt.Sprintf("Storager fault {%s}", s.Storager)
+}
+
+// Calls returns the count of calls on this Storager.
+func (s *Storage) Calls() int64 {
+	return atomic.LoadInt64(&s.calls)
+}
+
+// inject will add latency and return an error if this call should fail.
+func (s *Storage) inject(ctx context.Context, op string, path string) error {
+	n := atomic.AddInt64(&s.calls, 1)
+
+	if s.cfg.Latency > 0 {
+		timer := time.NewTimer(s.cfg.Latency)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return s.formatError(op, ctx.Err(), path)
+		}
+	}
+
+	if s.cfg.FailOnCall > 0 && n == s.cfg.FailOnCall {
+		return s.formatError(op, s.cfg.Err, path)
+	}
+	return nil
+}
+
+func (s *Storage) formatError(op string, err error, path ...string) error {
+	return services.StorageError{
+		Op:       op,
+		Err:      err,
+		Storager: s,
+		Path:     path,
+	}
+}
+
+// Delete implements Storager.Delete
+func (s *Storage) Delete(path string, pairs ...types.Pair) (err error) {
+	return s.DeleteWithContext(context.Background(), path, pairs...)
+}
+
+// DeleteWithContext implements Storager.DeleteWithContext
+func (s *Storage) DeleteWithContext(ctx context.Context, path string, pairs ...types.Pair) (err error) {
+	if err = s.inject(ctx, "delete", path); err != nil {
+		return
+	}
+	return s.Storager.DeleteWithContext(ctx, path, pairs...)
+}
+
+// List implements Storager.List
+func (s *Storage) List(path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	return s.ListWithContext(context.Background(), path, pairs...)
+}
+
+// ListWithContext implements Storager.ListWithContext
+func (s *Storage) ListWithContext(ctx context.Context, path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	if err = s.inject(ctx, "list", path); err != nil {
+		return
+	}
+	return s.Storager.ListWithContext(ctx, path, pairs...)
+}
+
+// Read implements Storager.Read
+func (s *Storage) Read(path string, w io.Writer, pairs ...types.Pair) (n int64, err error) {
+	return s.ReadWithContext(context.Background(), path, w, pairs...)
+}
+
+// ReadWithContext implements Storager.ReadWithContext
+func (s *Storage) ReadWithContext(ctx context.Context, path string, w io.Writer, pairs ...types.Pair) (n int64, err error) {
+	if err = s.inject(ctx, "read", path); err != nil {
+		return
+	}
+	if s.cfg.ShortRead > 0 {
+		w = &shortWriter{w: w, remain: s.cfg.ShortRead, err: s.cfg.Err}
+	}
+	return s.Storager.ReadWithContext(ctx, path, w, pairs...)
+}
+
+// Stat implements Storager.Stat
+func (s *Storage) Stat(path string, pairs ...types.Pair) (o *types.Object, err error) {
+	return s.StatWithContext(context.Background(), path, pairs...)
+}
+
+// StatWithContext implements Storager.StatWithContext
+func (s *Storage) StatWithContext(ctx context.Context, path string, pairs ...types.Pair) (o *types.Object, err error) {
+	if err = s.inject(ctx, "stat", path); err != nil {
+		return
+	}
+	return s.Storager.StatWithContext(ctx, path, pairs...)
+}
+
+// Write implements Storager.Write
+func (s *Storage) Write(path string, r io.Reader, size int64, pairs ...types.Pair) (n int64, err error) {
+	return s.WriteWithContext(context.Background(), path, r, size, pairs...)
+}
+
+// WriteWithContext implements Storager.WriteWithContext
+func (s *Storage) WriteWithContext(ctx context.Context, path string, r io.Reader, size int64, pairs ...types.Pair) (n int64, err error) {
+	if err = s.inject(ctx, "write", path); err != nil {
+		return
+	}
+	if r != nil {
+		r = s.wrapReader(r)
+	}
+	return s.Storager.WriteWithContext(ctx, path, r, size, pairs...)
+}
+
+// wrapReader wraps r with the reader faults.
+func (s *Storage) wrapReader(r io.Reader) io.Reader {
+	if s.cfg.TruncateWrite > 0 {
+		r = io.LimitReader(r, s.cfg.TruncateWrite)
+	}
+	if s.cfg.ReaderErrorAfter > 0 {
+		r = &errReader{r: r, remain: s.cfg.ReaderErrorAfter, err: s.cfg.Err}
+	}
+	return r
+}
+
+// errReader returns err after remain bytes read.
+type errReader struct {
+	r      io.Reader
+	remain int64
+	err    error
+}
+
+func (r *errReader) Read(p []byte) (n int, err error) {
+	if r.remain <= 0 {
+		return 0, r.err
+	}
+	if int64(len(p)) > r.remain {
+		p = p[:r.remain]
+	}
+	n, err = r.r.Read(p)
+	r.remain -= int64(n)
+	return
+}
+
+// shortWriter returns err after remain bytes written.
+type shortWriter struct {
+	w      io.Writer
+	remain int64
+	err    error
+}
+
+func (w *shortWriter) Write(p []byte) (n int, err error) {
+	if int64(len(p)) <= w.remain {
+		n, err = w.w.Write(p)
+		w.remain -= int64(n)
+		return
+	}
+
+	n, err = w.w.Write(p[:w.remain])
+	w.remain -= int64(n)
+	if err == nil {
+		err = w.err
+	}
+	return
+}
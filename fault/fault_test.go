@@ -0,0 +1,126 @@
+package fault
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+
+	"github.com/beyondstorage/go-integration-test/v4/memory"
+)
+
+func newStore(t *testing.T) types.Storager {
+	store, err := memory.NewStorager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestFailOnCall(t *testing.T) {
+	errCustom := errors.New("custom")
+	fs := New(newStore(t), Config{FailOnCall: 2, Err: errCustom})
+
+	_, err := fs.Stat("a")
+	if !errors.Is(err, services.ErrObjectNotExist) {
+		t.Fatalf("first call: expected ErrObjectNotExist, got %v", err)
+	}
+
+	_, err = fs.Stat("a")
+	if !errors.Is(err, errCustom) {
+		t.Fatalf("second call: expected injected error, got %v", err)
+	}
+	var se services.StorageError
+	if !errors.As(err, &se) || se.Op != "stat" || len(se.Path) != 1 || se.Path[0] != "a" {
+		t.Errorf("second call: expected StorageError with op and path, got %#v", err)
+	}
+
+	_, err = fs.Stat("a")
+	if !errors.Is(err, services.ErrObjectNotExist) {
+		t.Fatalf("third call: expected ErrObjectNotExist, got %v", err)
+	}
+	if fs.Calls() != 3 {
+		t.Errorf("expected 3 calls, got %d", fs.Calls())
+	}
+}
+
+func TestLatency(t *testing.T) {
+	fs := New(newStore(t), Config{Latency: 50 * time.Millisecond})
+
+	start := time.Now()
+	_, _ = fs.Stat("a")
+	if d := time.Since(start); d < 50*time.Millisecond {
+		t.Errorf("expected latency at least 50ms, got %s", d)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := fs.StatWithContext(ctx, "a")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestShortRead(t *testing.T) {
+	store := newStore(t)
+	content := []byte(strings.Repeat("abcdefgh", 1024))
+	_, err := store.Write("a", bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := New(store, Config{ShortRead: 100})
+
+	var buf bytes.Buffer
+	n, err := fs.Read("a", &buf)
+	if !errors.Is(err, ErrInjected) {
+		t.Fatalf("expected ErrInjected, got %v", err)
+	}
+	if n != 100 || !bytes.Equal(buf.Bytes(), content[:100]) {
+		t.Errorf("expected the first 100 bytes, got %d bytes", buf.Len())
+	}
+}
+
+func TestTruncateWrite(t *testing.T) {
+	store := newStore(t)
+	fs := New(store, Config{TruncateWrite: 4})
+
+	_, err := fs.Write("a", strings.NewReader("0123456789"), 10)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+	if _, err := store.Stat("a"); !errors.Is(err, services.ErrObjectNotExist) {
+		t.Errorf("expected no object, got %v", err)
+	}
+}
+
+func TestReaderErrorAfter(t *testing.T) {
+	store := newStore(t)
+	fs := New(store, Config{ReaderErrorAfter: 4})
+
+	_, err := fs.Write("a", strings.NewReader("0123456789"), 10)
+	if !errors.Is(err, ErrInjected) {
+		t.Fatalf("expected ErrInjected, got %v", err)
+	}
+	if _, err := store.Stat("a"); !errors.Is(err, services.ErrObjectNotExist) {
+		t.Errorf("expected no object, got %v", err)
+	}
+
+	// The zero Config injects nothing.
+	fs = New(store, Config{})
+	_, err = fs.Write("a", strings.NewReader("0123456789"), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := fs.Read("a", ioutil.Discard)
+	if err != nil || n != 10 {
+		t.Errorf("expected 10 bytes without error, got %d, %v", n, err)
+	}
+}
@@ -0,0 +1,228 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/beyondstorage/go-integration-test/v4/fault"
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+func TestFaultInjection(t *testing.T, store types.Storager) {
+	setupRand(t)
+
+	Convey("Given a basic Storager", t, func() {
+		So(store, ShouldNotBeNil)
+
+		Convey("When Write with a reader which fails midway", func() {
+			size := randInt63n(4*1024*1024) + 2 // Max file size is 4MB
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+			path := randUUID()
+
+			fs := fault.New(store, fault.Config{ReaderErrorAfter: randInt63n(size-1) + 1})
+			_, err := fs.Write(path, bytes.NewReader(content), size)
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			Convey("The error should be the injected error", func() {
+				So(err, ShouldNotBeNil)
+				So(errors.Is(err, fault.ErrInjected), ShouldBeTrue)
+			})
+
+			Convey("Stat should get nil Object and ObjectNotFound error", func() {
+				o, err := store.Stat(path)
+
+				So(errors.Is(err, services.ErrObjectNotExist), ShouldBeTrue)
+				So(o, ShouldBeNil)
+			})
+		})
+
+		Convey("When Write with a reader shorter than size", func() {
+			size := randInt63n(4*1024*1024) + 2 // Max file size is 4MB
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+			path := randUUID()
+
+			fs := fault.New(store, fault.Config{TruncateWrite: randInt63n(size-1) + 1})
+			_, err := fs.Write(path, bytes.NewReader(content), size)
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			Convey("The error should not be nil", func() {
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Stat should get nil Object and ObjectNotFound error", func() {
+				o, err := store.Stat(path)
+
+				So(errors.Is(err, services.ErrObjectNotExist), ShouldBeTrue)
+				So(o, ShouldBeNil)
+			})
+		})
+
+		Convey("When overwrite an existing file with a reader which fails midway", func() {
+			firstSize := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			first, _ := ioutil.ReadAll(io.LimitReader(randReader(), firstSize))
+			path := randUUID()
+
+			_, err := store.Write(path, bytes.NewReader(first), firstSize)
+			if err != nil {
+				t.Error(err)
+			}
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			secondSize := randInt63n(4*1024*1024) + 2 // Max file size is 4MB
+			second, _ := ioutil.ReadAll(io.LimitReader(randReader(), secondSize))
+
+			fs := fault.New(store, fault.Config{ReaderErrorAfter: randInt63n(secondSize-1) + 1})
+			_, err = fs.Write(path, bytes.NewReader(second), secondSize)
+
+			Convey("The error should be the injected error", func() {
+				So(errors.Is(err, fault.ErrInjected), ShouldBeTrue)
+			})
+
+			Convey("The content should not be partially overwritten", func() {
+				var buf bytes.Buffer
+				n, err := store.Read(path, &buf)
+
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, firstSize)
+				So(sha256.Sum256(buf.Bytes()), ShouldResemble, sha256.Sum256(first))
+			})
+		})
+
+		Convey("When Read with a short read", func() {
+			size := randInt63n(4*1024*1024) + 2 // Max file size is 4MB
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+			path := randUUID()
+
+			_, err := store.Write(path, bytes.NewReader(content), size)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			shortRead := randInt63n(size-1) + 1
+			fs := fault.New(store, fault.Config{ShortRead: shortRead})
+
+			var buf bytes.Buffer
+			n, err := fs.Read(path, &buf)
+
+			Convey("The error should be the injected error", func() {
+				So(err, ShouldNotBeNil)
+				So(errors.Is(err, fault.ErrInjected), ShouldBeTrue)
+			})
+
+			Convey("Only the content before the short read should be returned", func() {
+				So(n, ShouldEqual, shortRead)
+				So(sha256.Sum256(buf.Bytes()), ShouldResemble, sha256.Sum256(content[:shortRead]))
+			})
+		})
+
+		Convey("When operate with latency", func() {
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+			path := randUUID()
+
+			latency := 100 * time.Millisecond
+			fs := fault.New(store, fault.Config{Latency: latency})
+
+			start := time.Now()
+			_, err := fs.Write(path, bytes.NewReader(content), size)
+			elapsed := time.Since(start)
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The latency should be applied", func() {
+				So(elapsed, ShouldBeGreaterThanOrEqualTo, latency)
+			})
+
+			Convey("The content should be match", func() {
+				var buf bytes.Buffer
+				n, err := fs.Read(path, &buf)
+
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, size)
+				So(sha256.Sum256(buf.Bytes()), ShouldResemble, sha256.Sum256(content))
+			})
+		})
+
+		Convey("When the Nth call fails", func() {
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+			path := randUUID()
+
+			fs := fault.New(store, fault.Config{FailOnCall: 2})
+
+			_, err := fs.Write(path, bytes.NewReader(content), size)
+			if err != nil {
+				t.Error(err)
+			}
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			var buf bytes.Buffer
+			_, failedErr := fs.Read(path, &buf)
+
+			Convey("The failed call should return a StorageError with the injected error", func() {
+				So(errors.Is(failedErr, fault.ErrInjected), ShouldBeTrue)
+
+				var se services.StorageError
+				So(errors.As(failedErr, &se), ShouldBeTrue)
+				So(se.Op, ShouldEqual, "read")
+			})
+
+			Convey("The following calls should succeed", func() {
+				var buf bytes.Buffer
+				n, err := fs.Read(path, &buf)
+
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, size)
+				So(sha256.Sum256(buf.Bytes()), ShouldResemble, sha256.Sum256(content))
+			})
+		})
+	})
+}
@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+func TestFetcher(t *testing.T, store types.Storager) {
+	setupRand(t)
+
+	Convey("Given a basic Storager", t, func() {
+		f, ok := store.(types.Fetcher)
+		So(ok, ShouldBeTrue)
+
+		size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+		content, err := ioutil.ReadAll(io.LimitReader(randReader(), size))
+		if err != nil {
+			t.Error(err)
+		}
+
+		// All requests are served by a local server, so that we don't depend on outside network.
+		mux := http.NewServeMux()
+		mux.HandleFunc("/content", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+			_, _ = w.Write(content)
+		})
+		mux.HandleFunc("/not-exist", func(w http.ResponseWriter, r *http.Request) {
+			http.NotFound(w, r)
+		})
+		mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-time.After(10 * time.Second):
+				_, _ = w.Write(content)
+			case <-r.Context().Done():
+			}
+		})
+		mux.HandleFunc("/redirect/", func(w http.ResponseWriter, r *http.Request) {
+			hops, err := strconv.Atoi(r.URL.Path[len("/redirect/"):])
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if hops <= 0 {
+				http.Redirect(w, r, "/content", http.StatusFound)
+				return
+			}
+			http.Redirect(w, r, fmt.Sprintf("/redirect/%d", hops-1), http.StatusFound)
+		})
+
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		Convey("When Fetch a file", func() {
+			path := randUUID()
+			err := f.Fetch(path, server.URL+"/content")
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Stat should get Object without error", func() {
+				o, err := store.Stat(path)
+
+				So(err, ShouldBeNil)
+				So(o, ShouldNotBeNil)
+				So(o.Path, ShouldEqual, path)
+
+				osize, ok := o.GetContentLength()
+				So(ok, ShouldBeTrue)
+				So(osize, ShouldEqual, size)
+			})
+
+			Convey("Read should get Object data without error", func() {
+				var buf bytes.Buffer
+				n, err := store.Read(path, &buf)
+
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, size)
+				So(sha256.Sum256(buf.Bytes()), ShouldResemble, sha256.Sum256(content))
+			})
+		})
+
+		Convey("When Fetch to an existing file", func() {
+			path := randUUID()
+			existSize := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			_, err := store.Write(path, io.LimitReader(randReader(), existSize), existSize)
+			if err != nil {
+				t.Error(err)
+			}
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			err = f.Fetch(path, server.URL+"/content")
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Read should get the fetched data", func() {
+				var buf bytes.Buffer
+				n, err := store.Read(path, &buf)
+
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, size)
+				So(sha256.Sum256(buf.Bytes()), ShouldResemble, sha256.Sum256(content))
+			})
+		})
+
+		Convey("When Fetch from a redirect chain", func() {
+			path := randUUID()
+			err := f.Fetch(path, server.URL+"/redirect/3")
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Read should get the redirected data", func() {
+				var buf bytes.Buffer
+				n, err := store.Read(path, &buf)
+
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, size)
+				So(sha256.Sum256(buf.Bytes()), ShouldResemble, sha256.Sum256(content))
+			})
+		})
+
+		Convey("When Fetch from a not existing url", func() {
+			path := randUUID()
+			err := f.Fetch(path, server.URL+"/not-exist")
+
+			Convey("The error should not be nil", func() {
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Stat should get nil Object and ObjectNotFound error", func() {
+				o, err := store.Stat(path)
+
+				So(errors.Is(err, services.ErrObjectNotExist), ShouldBeTrue)
+				So(o, ShouldBeNil)
+			})
+		})
+
+		Convey("When Fetch from a slow server", func() {
+			path := randUUID()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			err := f.FetchWithContext(ctx, path, server.URL+"/slow")
+
+			Convey("The error should not be nil", func() {
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Stat should get nil Object and ObjectNotFound error", func() {
+				o, err := store.Stat(path)
+
+				So(errors.Is(err, services.ErrObjectNotExist), ShouldBeTrue)
+				So(o, ShouldBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// largeReadChunk is the size read at every offset in TestLargeObject.
+const largeReadChunk = 1024 * 1024
+
+// patternReader generates content which could be regenerated at any offset without
+// generating the content before it, so that large objects never need to be held in memory.
+type patternReader struct {
+	seed   uint64
+	offset int64
+	end    int64
+}
+
+func newPatternReader(seed uint64, offset, size int64) *patternReader {
+	return &patternReader{seed: seed, offset: offset, end: offset + size}
+}
+
+// patternByte returns the byte at offset, every 8 bytes is a mixed word of its index.
+func patternByte(seed uint64, offset int64) byte {
+	word := uint64(offset/8)*0x9E3779B97F4A7C15 ^ seed
+	return byte(word >> (uint(offset%8) * 8))
+}
+
+func (r *patternReader) Read(p []byte) (int, error) {
+	if r.offset >= r.end {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > r.end-r.offset {
+		p = p[:r.end-r.offset]
+	}
+	for i := range p {
+		p[i] = patternByte(r.seed, r.offset+int64(i))
+	}
+	r.offset += int64(len(p))
+	return len(p), nil
+}
+
+// hashWriter is a sha256 writer which counts written bytes.
+type hashWriter struct {
+	hash.Hash
+	n int64
+}
+
+func (w *hashWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return w.Hash.Write(p)
+}
+
+func logThroughput(t *testing.T, op string, n int64, d time.Duration) {
+	t.Logf("%s %d bytes in %s, %.2f MiB/s", op, n, d, float64(n)/1024/1024/d.Seconds())
+}
+
+// largeOffsets returns the offsets to read in an object with size.
+func largeOffsets(size, chunk int64) []int64 {
+	candidates := []int64{0, size / 2, size - chunk}
+	// Check offsets around and beyond 4GiB if possible.
+	if size > 1<<32+chunk {
+		candidates = append(candidates,
+			1<<32-chunk/2,
+			1<<32,
+			1<<32+randInt63n(size-1<<32-chunk),
+		)
+	}
+
+	seen := make(map[int64]bool)
+	var offsets []int64
+	for _, v := range candidates {
+		if v < 0 || seen[v] {
+			continue
+		}
+		seen[v] = true
+		offsets = append(offsets, v)
+	}
+	return offsets
+}
+
+// TestLargeObject streams an object with size through Write and Read, the content is
+// generated while writing and verified while reading without being held in memory.
+//
+// This suite is slow for large size, and it's only run by RunAll while
+// Options.LargeObjectSize is set. Use a size larger than 4GiB to check offsets
+// beyond 2^32, and the service must accept a single Write with size.
+func TestLargeObject(t *testing.T, store types.Storager, size int64) {
+	setupRand(t)
+
+	// The object is written outside Convey, as Convey will run the setup again for
+	// every leaf scope.
+	path := randUUID()
+	seed := uint64(randInt63())
+
+	w := &hashWriter{Hash: sha256.New()}
+	start := time.Now()
+	_, err := store.Write(path, io.TeeReader(newPatternReader(seed, 0, size), w), size)
+	logThroughput(t, "write", w.n, time.Since(start))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := w.Sum(nil)
+
+	defer func() {
+		err := store.Delete(path)
+		if err != nil {
+			t.Error(err)
+		}
+	}()
+
+	chunk := int64(largeReadChunk)
+	if chunk > size {
+		chunk = size
+	}
+	// Offsets must be picked outside Convey to keep the same scopes in every run.
+	offsets := largeOffsets(size, chunk)
+
+	Convey("Given a basic Storager", t, func() {
+		So(store, ShouldNotBeNil)
+
+		Convey("When Stat the large object", func() {
+			o, err := store.Stat(path)
+
+			Convey("The size should be match", func() {
+				So(err, ShouldBeNil)
+
+				osize, ok := o.GetContentLength()
+				So(ok, ShouldBeTrue)
+				So(osize, ShouldEqual, size)
+			})
+		})
+
+		Convey("When Read the large object", func() {
+			w := &hashWriter{Hash: sha256.New()}
+			start := time.Now()
+			n, err := store.Read(path, w)
+			logThroughput(t, "read", w.n, time.Since(start))
+
+			Convey("The content should be match", func() {
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, size)
+				So(w.n, ShouldEqual, size)
+				So(w.Sum(nil), ShouldResemble, sum)
+			})
+		})
+
+		for _, offset := range offsets {
+			offset := offset
+			length := chunk
+			if offset+length > size {
+				length = size - offset
+			}
+			if length == 0 {
+				continue
+			}
+
+			Convey(fmt.Sprintf("When Read %d bytes at offset %d", length, offset), func() {
+				var buf bytes.Buffer
+				n, err := store.Read(path, &buf, pairs.WithOffset(offset), pairs.WithSize(length))
+
+				Convey("The content should be match", func() {
+					So(err, ShouldBeNil)
+					So(n, ShouldEqual, length)
+
+					expected, _ := ioutil.ReadAll(newPatternReader(seed, offset, length))
+					So(sha256.Sum256(buf.Bytes()), ShouldResemble, sha256.Sum256(expected))
+				})
+			})
+		}
+	})
+}
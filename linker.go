@@ -2,18 +2,17 @@ package tests
 
 import (
 	"io"
-	"math/rand"
 	"path/filepath"
 	"testing"
 
-	"github.com/google/uuid"
 	. "github.com/smartystreets/goconvey/convey"
 
-	"github.com/beyondstorage/go-storage/v4/pkg/randbytes"
 	"github.com/beyondstorage/go-storage/v4/types"
 )
 
 func TestLinker(t *testing.T, store types.Storager) {
+	setupRand(t)
+
 	Convey("Given a basic Storager", t, func() {
 		l, ok := store.(types.Linker)
 		So(ok, ShouldBeTrue)
@@ -21,9 +20,9 @@ func TestLinker(t *testing.T, store types.Storager) {
 		workDir := store.Metadata().WorkDir
 
 		Convey("When create a link object", func() {
-			size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			r := io.LimitReader(randbytes.NewRand(), size)
-			target := uuid.New().String()
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			r := io.LimitReader(randReader(), size)
+			target := randUUID()
 
 			_, err := store.Write(target, r, size)
 			if err != nil {
@@ -37,7 +36,7 @@ func TestLinker(t *testing.T, store types.Storager) {
 				}
 			}()
 
-			path := uuid.New().String()
+			path := randUUID()
 			o, err := l.CreateLink(path, target)
 
 			defer func() {
@@ -87,9 +86,9 @@ func TestLinker(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When create a link object from a not existing target", func() {
-			target := uuid.New().String()
+			target := randUUID()
 
-			path := uuid.New().String()
+			path := randUUID()
 			o, err := l.CreateLink(path, target)
 
 			defer func() {
@@ -138,9 +137,9 @@ func TestLinker(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When CreateLink to an existing path", func() {
-			firstSize := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			firstR := io.LimitReader(randbytes.NewRand(), firstSize)
-			firstTarget := uuid.New().String()
+			firstSize := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			firstR := io.LimitReader(randReader(), firstSize)
+			firstTarget := randUUID()
 
 			_, err := store.Write(firstTarget, firstR, firstSize)
 			if err != nil {
@@ -154,7 +153,7 @@ func TestLinker(t *testing.T, store types.Storager) {
 				}
 			}()
 
-			path := uuid.New().String()
+			path := randUUID()
 			o, err := l.CreateLink(path, firstTarget)
 
 			defer func() {
@@ -168,9 +167,9 @@ func TestLinker(t *testing.T, store types.Storager) {
 				So(err, ShouldBeNil)
 			})
 
-			secondSize := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			secondR := io.LimitReader(randbytes.NewRand(), secondSize)
-			secondTarget := uuid.New().String()
+			secondSize := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			secondR := io.LimitReader(randReader(), secondSize)
+			secondTarget := randUUID()
 
 			_, err = store.Write(secondTarget, secondR, secondSize)
 			if err != nil {
@@ -0,0 +1,339 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// listLayout is the dirs and the number of files under them used in TestListPagination.
+//
+// Most services page their list calls at 1000 keys, so dirs hold more files than one page.
+var listLayout = []struct {
+	dir   string
+	files int
+}{
+	{"", 1100},
+	{"p0/", 400},
+	{"p0/q/", 500},
+	{"p1/", 400},
+	{"p2/", 400},
+}
+
+// listEntry is an object returned by List.
+type listEntry struct {
+	dir  bool
+	size int64
+}
+
+// listAll lists path with mode until done, and returns all entries keyed by path
+// without trailing slash, along with paths returned more than once.
+func listAll(store types.Storager, path string, mode types.ListMode) (entries map[string]listEntry, dups []string, err error) {
+	it, err := store.List(path, pairs.WithListMode(mode))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries = make(map[string]listEntry)
+	for {
+		o, err := it.Next()
+		if err == types.IterateDone {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Some services return dirs with a trailing slash.
+		p := strings.TrimSuffix(o.Path, "/")
+		if _, ok := entries[p]; ok {
+			dups = append(dups, p)
+			continue
+		}
+
+		e := listEntry{dir: o.Mode.IsDir()}
+		if !e.dir {
+			e.size, _ = o.GetContentLength()
+		}
+		entries[p] = e
+	}
+
+	// Iterator should keep returning IterateDone after done.
+	if _, err := it.Next(); err != types.IterateDone {
+		return nil, nil, fmt.Errorf("next after done: %v", err)
+	}
+	return entries, dups, nil
+}
+
+// diffEntries returns the paths in expected which are missing or mismatched in actual.
+func diffEntries(expected, actual map[string]listEntry) []string {
+	var diff []string
+	for path, e := range expected {
+		if v, ok := actual[path]; !ok || v != e {
+			diff = append(diff, path)
+		}
+	}
+	sort.Strings(diff)
+	return diff
+}
+
+// forEach calls fn on paths with concurrency goroutines, and returns all errors.
+func forEach(paths []string, fn func(path string) error) []error {
+	ch := make(chan string)
+	go func() {
+		defer close(ch)
+		for _, path := range paths {
+			ch <- path
+		}
+	}()
+
+	var errs errorList
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range ch {
+				if err := fn(path); err != nil {
+					errs.Add("%s: %w", path, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return errs.Errors()
+}
+
+// TestListPagination writes and deletes thousands of objects, it's only run by RunAll
+// while Options.ListPagination is set.
+func TestListPagination(t *testing.T, store types.Storager) {
+	setupRand(t)
+
+	// Objects are created outside Convey, as Convey will run the setup again for
+	// every leaf scope and creating thousands of objects is expensive.
+	base := randUUID()
+
+	sizes := make(map[string]int64)
+	readers := make(map[string]io.Reader)
+	var paths []string
+	for _, l := range listLayout {
+		for i := 0; i < l.files; i++ {
+			path := base + "/" + l.dir + randUUID()
+			sizes[path] = randInt63n(1024) + 1
+			readers[path] = randReader()
+			paths = append(paths, path)
+		}
+	}
+
+	// Cleanup is registered before writing, so that written objects will be deleted
+	// even if some writes failed.
+	defer func() {
+		errs := forEach(paths, func(path string) error {
+			return store.Delete(path)
+		})
+		for _, err := range errs {
+			t.Error(err)
+		}
+	}()
+
+	errs := forEach(paths, func(path string) error {
+		size := sizes[path]
+		_, err := store.Write(path, io.LimitReader(readers[path], size), size)
+		return err
+	})
+	if len(errs) > 0 {
+		t.Fatalf("write objects: %v", errs)
+	}
+
+	Convey("Given a basic Storager", t, func() {
+		So(store, ShouldNotBeNil)
+
+		Convey("When List with ListModePrefix", func() {
+			entries, dups, err := listAll(store, base+"/", types.ListModePrefix)
+			if errors.Is(err, services.ErrListModeInvalid) {
+				t.Logf("%s doesn't support ListModePrefix", store)
+				return
+			}
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("There should be no duplicated objects", func() {
+				So(dups, ShouldBeEmpty)
+			})
+
+			Convey("All objects should be returned with correct sizes", func() {
+				expected := make(map[string]listEntry, len(sizes))
+				for path, size := range sizes {
+					expected[path] = listEntry{size: size}
+				}
+
+				So(len(entries), ShouldEqual, len(expected))
+				So(diffEntries(expected, entries), ShouldBeEmpty)
+			})
+		})
+
+		for _, l := range listLayout {
+			dir := l.dir
+
+			Convey(fmt.Sprintf("When List dir %q with ListModeDir", dir), func() {
+				entries, dups, err := listAll(store, base+"/"+dir, types.ListModeDir)
+
+				Convey("The error should be nil", func() {
+					So(err, ShouldBeNil)
+				})
+
+				Convey("There should be no duplicated objects", func() {
+					So(dups, ShouldBeEmpty)
+				})
+
+				Convey("Only direct files and dirs should be returned", func() {
+					expected := make(map[string]listEntry)
+					for path, size := range sizes {
+						if !strings.HasPrefix(path, base+"/"+dir) {
+							continue
+						}
+						rest := strings.TrimPrefix(path, base+"/"+dir)
+						if idx := strings.Index(rest, "/"); idx >= 0 {
+							expected[base+"/"+dir+rest[:idx]] = listEntry{dir: true}
+							continue
+						}
+						expected[path] = listEntry{size: size}
+					}
+
+					So(len(entries), ShouldEqual, len(expected))
+					So(diffEntries(expected, entries), ShouldBeEmpty)
+				})
+			})
+		}
+
+		Convey("When ListWithContext and cancel the context during listing", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			it, err := store.ListWithContext(ctx, base+"/", pairs.WithListMode(types.ListModeDir))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = it.Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cancel()
+
+			// Objects in the fetched page could still be returned, but the iterator
+			// must not fetch the next page from the service. The base dir holds more
+			// files than one page, so the iterator can't finish without fetching.
+			count := 1
+			for {
+				_, err = it.Next()
+				if err != nil {
+					break
+				}
+				count++
+			}
+
+			Convey("The iterator should return context.Canceled before all objects returned", func() {
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+				So(count, ShouldBeLessThan, listLayout[0].files)
+			})
+		})
+	})
+}
+
+func TestListHierarchy(t *testing.T, store types.Storager) {
+	setupRand(t)
+
+	Convey("Given a basic Storager", t, func() {
+		So(store, ShouldNotBeNil)
+
+		Convey("When List a tree like a/b/c, a/d and e", func() {
+			base := randUUID()
+
+			sizes := map[string]int64{
+				base + "/a/b/c": randInt63n(1024) + 1,
+				base + "/a/d":   randInt63n(1024) + 1,
+				base + "/e":     randInt63n(1024) + 1,
+			}
+			for _, path := range []string{base + "/a/b/c", base + "/a/d", base + "/e"} {
+				_, err := store.Write(path, io.LimitReader(randReader(), sizes[path]), sizes[path])
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			defer func() {
+				for path := range sizes {
+					err := store.Delete(path)
+					if err != nil {
+						t.Error(err)
+					}
+				}
+			}()
+
+			dir := listEntry{dir: true}
+			file := func(path string) listEntry {
+				return listEntry{size: sizes[base+"/"+path]}
+			}
+
+			cases := []struct {
+				path     string
+				mode     types.ListMode
+				expected map[string]listEntry
+			}{
+				{base + "/", types.ListModeDir, map[string]listEntry{"a": dir, "e": file("e")}},
+				{base + "/a/", types.ListModeDir, map[string]listEntry{"a/b": dir, "a/d": file("a/d")}},
+				{base + "\\a\\", types.ListModeDir, map[string]listEntry{"a/b": dir, "a/d": file("a/d")}},
+				{base + "/a/b/", types.ListModeDir, map[string]listEntry{"a/b/c": file("a/b/c")}},
+				{base + "\\a\\b\\", types.ListModeDir, map[string]listEntry{"a/b/c": file("a/b/c")}},
+				{base + "/", types.ListModePrefix, map[string]listEntry{"a/b/c": file("a/b/c"), "a/d": file("a/d"), "e": file("e")}},
+				{base + "/a", types.ListModePrefix, map[string]listEntry{"a/b/c": file("a/b/c"), "a/d": file("a/d")}},
+				{base + "/a/", types.ListModePrefix, map[string]listEntry{"a/b/c": file("a/b/c"), "a/d": file("a/d")}},
+				{base + "\\a\\", types.ListModePrefix, map[string]listEntry{"a/b/c": file("a/b/c"), "a/d": file("a/d")}},
+			}
+
+			for _, c := range cases {
+				c := c
+
+				Convey(fmt.Sprintf("When List %q with %s", strings.TrimPrefix(c.path, base), c.mode), func() {
+					entries, dups, err := listAll(store, c.path, c.mode)
+					if c.mode.IsPrefix() && errors.Is(err, services.ErrListModeInvalid) {
+						t.Logf("%s doesn't support ListModePrefix", store)
+						return
+					}
+
+					Convey("The error should be nil", func() {
+						So(err, ShouldBeNil)
+					})
+
+					Convey("There should be no duplicated objects", func() {
+						So(dups, ShouldBeEmpty)
+					})
+
+					Convey("The entries and modes should be match", func() {
+						// Paths returned by List always use slash, see GSP-749.
+						expected := make(map[string]listEntry, len(c.expected))
+						for path, e := range c.expected {
+							expected[base+"/"+path] = e
+						}
+
+						So(entries, ShouldResemble, expected)
+					})
+				})
+			}
+		})
+	})
+}
@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// CreateAppend implements Appender.CreateAppend
+func (s *Storage) CreateAppend(path string, pairs ...types.Pair) (o *types.Object, err error) {
+	return s.CreateAppendWithContext(context.Background(), path, pairs...)
+}
+
+// CreateAppendWithContext implements Appender.CreateAppendWithContext
+func (s *Storage) CreateAppendWithContext(ctx context.Context, path string, pairs ...types.Pair) (o *types.Object, err error) {
+	defer func() {
+		err = s.formatError("create_append", err, path)
+	}()
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	opt, err := parseOptions(s.withDefaultPairs(pairs))
+	if err != nil {
+		return
+	}
+	key := s.absPath(path)
+
+	v := &object{
+		mode:         types.ModeRead | types.ModeAppend,
+		content:      []byte{},
+		contentType:  opt.contentType,
+		etag:         etag(nil),
+		lastModified: time.Now(),
+		appendable:   true,
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if ov, ok := s.objects[key]; ok && ov.mode.IsDir() {
+		return nil, services.ObjectModeInvalidError{Expected: types.ModeAppend, Actual: ov.mode}
+	}
+	// Existing object will be overwritten.
+	s.objects[key] = v
+	return s.newObject(normalizePath(path), key, v), nil
+}
+
+// WriteAppend implements Appender.WriteAppend
+func (s *Storage) WriteAppend(o *types.Object, r io.Reader, size int64, pairs ...types.Pair) (n int64, err error) {
+	return s.WriteAppendWithContext(context.Background(), o, r, size, pairs...)
+}
+
+// WriteAppendWithContext implements Appender.WriteAppendWithContext
+func (s *Storage) WriteAppendWithContext(ctx context.Context, o *types.Object, r io.Reader, size int64, pairs ...types.Pair) (n int64, err error) {
+	defer func() {
+		err = s.formatError("write_append", err, o.Path)
+	}()
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	opt, err := parseOptions(s.withDefaultPairs(pairs))
+	if err != nil {
+		return
+	}
+
+	content, err := readFull(ctx, r, size, opt.ioCallback)
+	if err != nil {
+		return 0, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	v, ok := s.objects[o.ID]
+	if !ok {
+		return 0, services.ErrObjectNotExist
+	}
+	if !v.appendable {
+		return 0, services.ObjectModeInvalidError{Expected: types.ModeAppend, Actual: v.mode}
+	}
+
+	v.content = append(v.content, content...)
+	v.etag = etag(v.content)
+	v.lastModified = time.Now()
+
+	o.SetAppendOffset(int64(len(v.content)))
+	return size, nil
+}
+
+// CommitAppend implements Appender.CommitAppend
+func (s *Storage) CommitAppend(o *types.Object, pairs ...types.Pair) (err error) {
+	return s.CommitAppendWithContext(context.Background(), o, pairs...)
+}
+
+// CommitAppendWithContext implements Appender.CommitAppendWithContext
+func (s *Storage) CommitAppendWithContext(ctx context.Context, o *types.Object, pairs ...types.Pair) (err error) {
+	defer func() {
+		err = s.formatError("commit_append", err, o.Path)
+	}()
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	v, ok := s.objects[o.ID]
+	if !ok {
+		return services.ErrObjectNotExist
+	}
+	if !v.appendable {
+		return services.ObjectModeInvalidError{Expected: types.ModeAppend, Actual: v.mode}
+	}
+
+	// Committed object could not be appended anymore.
+	v.appendable = false
+	v.mode = types.ModeRead
+	return nil
+}
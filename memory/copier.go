@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// Copy implements Copier.Copy
+func (s *Storage) Copy(src string, dst string, pairs ...types.Pair) (err error) {
+	return s.CopyWithContext(context.Background(), src, dst, pairs...)
+}
+
+// CopyWithContext implements Copier.CopyWithContext
+func (s *Storage) CopyWithContext(ctx context.Context, src string, dst string, pairs ...types.Pair) (err error) {
+	defer func() {
+		err = s.formatError("copy", err, src, dst)
+	}()
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	v, err := s.transferable(s.absPath(src), s.absPath(dst))
+	if err != nil {
+		return err
+	}
+
+	nv := v.clone()
+	nv.lastModified = time.Now()
+	s.objects[s.absPath(dst)] = nv
+	return nil
+}
+
+// transferable checks whether the object at src could be copied or moved to dst,
+// and returns the src object.
+//
+// Caller MUST hold the lock.
+func (s *Storage) transferable(src, dst string) (*object, error) {
+	v, ok := s.objects[src]
+	if !ok {
+		return nil, services.ErrObjectNotExist
+	}
+	if v.mode.IsDir() {
+		return nil, services.ObjectModeInvalidError{Expected: types.ModeRead, Actual: v.mode}
+	}
+	// Copy or move a file to a dir should return ErrObjectModeInvalid.
+	if dv, ok := s.objects[dst]; ok && dv.mode.IsDir() {
+		return nil, services.ObjectModeInvalidError{Expected: types.ModeRead, Actual: dv.mode}
+	}
+	return v, nil
+}
@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// CreateDir implements Direr.CreateDir
+func (s *Storage) CreateDir(path string, pairs ...types.Pair) (o *types.Object, err error) {
+	return s.CreateDirWithContext(context.Background(), path, pairs...)
+}
+
+// CreateDirWithContext implements Direr.CreateDirWithContext
+func (s *Storage) CreateDirWithContext(ctx context.Context, path string, pairs ...types.Pair) (o *types.Object, err error) {
+	defer func() {
+		err = s.formatError("create_dir", err, path)
+	}()
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	key := s.absPath(path)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	v, ok := s.objects[key]
+	if ok && !v.mode.IsDir() {
+		return nil, services.ObjectModeInvalidError{Expected: types.ModeDir, Actual: v.mode}
+	}
+	if !ok {
+		v = &object{
+			mode:         types.ModeDir,
+			lastModified: time.Now(),
+		}
+		s.objects[key] = v
+	}
+	return s.newObject(normalizePath(path), key, v), nil
+}
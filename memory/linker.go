@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// CreateLink implements Linker.CreateLink
+func (s *Storage) CreateLink(path string, target string, pairs ...types.Pair) (o *types.Object, err error) {
+	return s.CreateLinkWithContext(context.Background(), path, target, pairs...)
+}
+
+// CreateLinkWithContext implements Linker.CreateLinkWithContext
+func (s *Storage) CreateLinkWithContext(ctx context.Context, path string, target string, pairs ...types.Pair) (o *types.Object, err error) {
+	defer func() {
+		err = s.formatError("create_link", err, path, target)
+	}()
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	key := s.absPath(path)
+
+	// The target doesn't need to exist.
+	v := &object{
+		mode:         types.ModeLink,
+		linkTarget:   s.absPath(target),
+		lastModified: time.Now(),
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if ov, ok := s.objects[key]; ok && ov.mode.IsDir() {
+		return nil, services.ObjectModeInvalidError{Expected: types.ModeLink, Actual: ov.mode}
+	}
+	s.objects[key] = v
+	return s.newObject(normalizePath(path), key, v), nil
+}
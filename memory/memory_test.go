@@ -0,0 +1,31 @@
+package memory_test
+
+import (
+	"testing"
+
+	tests "github.com/beyondstorage/go-integration-test/v4"
+	"github.com/beyondstorage/go-integration-test/v4/memory"
+)
+
+// TestIntegration runs all suites against the in-memory Storager, so that the suites
+// and memory are checked against each other.
+func TestIntegration(t *testing.T) {
+	store, err := memory.NewStorager()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// memory has no restrictions on part numbers and sizes.
+	p := tests.Profile{
+		DiscontinuousPartNumbers: true,
+		MinPartSize:              1,
+		UserMetadataPairKey:      memory.UserMetadataPairKey,
+	}
+
+	tests.RunAll(t, store, tests.Options{
+		Profile:         &p,
+		LargeObjectSize: 16 * 1024 * 1024,
+		ListPagination:  true,
+		NewStorager:     memory.NewStorager,
+	})
+}
@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// Move implements Mover.Move
+func (s *Storage) Move(src string, dst string, pairs ...types.Pair) (err error) {
+	return s.MoveWithContext(context.Background(), src, dst, pairs...)
+}
+
+// MoveWithContext implements Mover.MoveWithContext
+func (s *Storage) MoveWithContext(ctx context.Context, src string, dst string, pairs ...types.Pair) (err error) {
+	defer func() {
+		err = s.formatError("move", err, src, dst)
+	}()
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	srcKey, dstKey := s.absPath(src), s.absPath(dst)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	v, err := s.transferable(srcKey, dstKey)
+	if err != nil {
+		return err
+	}
+
+	if srcKey != dstKey {
+		s.objects[dstKey] = v
+		delete(s.objects, srcKey)
+	}
+	return nil
+}
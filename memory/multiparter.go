@@ -0,0 +1,279 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// multipart is an in progress multipart upload.
+type multipart struct {
+	id  string
+	key string
+
+	contentType string
+	// parts is keyed by the part index.
+	parts map[int]*part
+}
+
+type part struct {
+	content []byte
+	etag    string
+}
+
+// CreateMultipart implements Multiparter.CreateMultipart
+func (s *Storage) CreateMultipart(path string, pairs ...types.Pair) (o *types.Object, err error) {
+	return s.CreateMultipartWithContext(context.Background(), path, pairs...)
+}
+
+// CreateMultipartWithContext implements Multiparter.CreateMultipartWithContext
+func (s *Storage) CreateMultipartWithContext(ctx context.Context, path string, pairs ...types.Pair) (o *types.Object, err error) {
+	defer func() {
+		err = s.formatError("create_multipart", err, path)
+	}()
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	opt, err := parseOptions(s.withDefaultPairs(pairs))
+	if err != nil {
+		return
+	}
+
+	// Every CreateMultipart starts a new upload, even for the same path.
+	m := &multipart{
+		id:          uuid.New().String(),
+		key:         s.absPath(path),
+		contentType: opt.contentType,
+		parts:       make(map[int]*part),
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.multiparts[m.id] = m
+	return s.newMultipartObject(normalizePath(path), m), nil
+}
+
+// WriteMultipart implements Multiparter.WriteMultipart
+func (s *Storage) WriteMultipart(o *types.Object, r io.Reader, size int64, index int, pairs ...types.Pair) (n int64, part *types.Part, err error) {
+	return s.WriteMultipartWithContext(context.Background(), o, r, size, index, pairs...)
+}
+
+// WriteMultipartWithContext implements Multiparter.WriteMultipartWithContext
+func (s *Storage) WriteMultipartWithContext(ctx context.Context, o *types.Object, r io.Reader, size int64, index int, pairs ...types.Pair) (n int64, p *types.Part, err error) {
+	defer func() {
+		err = s.formatError("write_multipart", err, o.Path)
+	}()
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	if index < 0 {
+		return 0, nil, fmt.Errorf("part index %d is negative: %w", index, services.ErrRestrictionDissatisfied)
+	}
+
+	opt, err := parseOptions(s.withDefaultPairs(pairs))
+	if err != nil {
+		return
+	}
+
+	content, err := readFull(ctx, r, size, opt.ioCallback)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	m, err := s.getMultipart(o)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// Write to an existing index will overwrite the part.
+	v := &part{content: content, etag: etag(content)}
+	m.parts[index] = v
+
+	return size, &types.Part{Index: index, Size: size, ETag: v.etag}, nil
+}
+
+// ListMultipart implements Multiparter.ListMultipart
+func (s *Storage) ListMultipart(o *types.Object, pairs ...types.Pair) (pi *types.PartIterator, err error) {
+	return s.ListMultipartWithContext(context.Background(), o, pairs...)
+}
+
+// ListMultipartWithContext implements Multiparter.ListMultipartWithContext
+func (s *Storage) ListMultipartWithContext(ctx context.Context, o *types.Object, pairs ...types.Pair) (pi *types.PartIterator, err error) {
+	defer func() {
+		err = s.formatError("list_multipart", err, o.Path)
+	}()
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	m, err := s.getMultipart(o)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]*types.Part, 0, len(m.parts))
+	for index, v := range m.parts {
+		parts = append(parts, &types.Part{
+			Index: index,
+			Size:  int64(len(v.content)),
+			ETag:  v.etag,
+		})
+	}
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].Index < parts[j].Index
+	})
+
+	input := &partPageStatus{parts: parts}
+	return types.NewPartIterator(ctx, nextPartPage, input), nil
+}
+
+type partPageStatus struct {
+	parts  []*types.Part
+	offset int
+}
+
+func (i *partPageStatus) ContinuationToken() string {
+	return strconv.Itoa(i.offset)
+}
+
+func nextPartPage(ctx context.Context, page *types.PartPage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	input := page.Status.(*partPageStatus)
+
+	end := input.offset + listPageSize
+	if end > len(input.parts) {
+		end = len(input.parts)
+	}
+	page.Data = append(page.Data, input.parts[input.offset:end]...)
+	input.offset = end
+
+	if input.offset >= len(input.parts) {
+		return types.IterateDone
+	}
+	return nil
+}
+
+// CompleteMultipart implements Multiparter.CompleteMultipart
+func (s *Storage) CompleteMultipart(o *types.Object, parts []*types.Part, pairs ...types.Pair) (err error) {
+	return s.CompleteMultipartWithContext(context.Background(), o, parts, pairs...)
+}
+
+// CompleteMultipartWithContext implements Multiparter.CompleteMultipartWithContext
+//
+// parts must not be empty, and must be sorted by index without duplication.
+func (s *Storage) CompleteMultipartWithContext(ctx context.Context, o *types.Object, parts []*types.Part, pairs ...types.Pair) (err error) {
+	defer func() {
+		err = s.formatError("complete_multipart", err, o.Path)
+	}()
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	if len(parts) == 0 {
+		return fmt.Errorf("parts is empty: %w", services.ErrRestrictionDissatisfied)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	m, err := s.getMultipart(o)
+	if err != nil {
+		return err
+	}
+
+	var content []byte
+	for i, p := range parts {
+		if i > 0 && p.Index <= parts[i-1].Index {
+			return fmt.Errorf("part %d is not in ascending order: %w", p.Index, services.ErrRestrictionDissatisfied)
+		}
+
+		v, ok := m.parts[p.Index]
+		if !ok {
+			return fmt.Errorf("part %d is not uploaded: %w", p.Index, services.ErrRestrictionDissatisfied)
+		}
+		if p.ETag != "" && p.ETag != v.etag {
+			return fmt.Errorf("part %d etag mismatch: %w", p.Index, services.ErrRestrictionDissatisfied)
+		}
+		content = append(content, v.content...)
+	}
+
+	if ov, ok := s.objects[m.key]; ok && ov.mode.IsDir() {
+		return services.ObjectModeInvalidError{Expected: types.ModeRead, Actual: ov.mode}
+	}
+
+	s.objects[m.key] = &object{
+		mode:         types.ModeRead,
+		content:      content,
+		contentType:  m.contentType,
+		etag:         etag(content),
+		lastModified: time.Now(),
+	}
+	delete(s.multiparts, m.id)
+	return nil
+}
+
+// getMultipart returns the multipart upload of o.
+//
+// Caller MUST hold the lock.
+func (s *Storage) getMultipart(o *types.Object) (*multipart, error) {
+	id, ok := o.GetMultipartID()
+	if !ok {
+		return nil, services.PairRequiredError{Keys: []string{"multipart_id"}}
+	}
+	m, ok := s.multiparts[id]
+	if !ok || m.key != s.absPath(o.Path) {
+		return nil, services.ErrObjectNotExist
+	}
+	return m, nil
+}
+
+// listPart lists all multipart uploads which have the prefix path.
+func (s *Storage) listPart(path string) []*types.Object {
+	prefix := normalizePath(path)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var objects []*types.Object
+	for _, m := range s.multiparts {
+		rel, ok := s.relPath(m.key)
+		if !ok || !strings.HasPrefix(rel, prefix) {
+			continue
+		}
+		objects = append(objects, s.newMultipartObject(rel, m))
+	}
+	return objects
+}
+
+// newMultipartObject creates a types.Object from multipart.
+func (s *Storage) newMultipartObject(path string, m *multipart) *types.Object {
+	o := types.NewObject(s, true)
+	o.ID = m.key
+	o.Path = path
+	o.Mode = types.ModePart
+	o.SetMultipartID(m.id)
+	return o
+}
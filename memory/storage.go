@@ -0,0 +1,518 @@
+// Package memory provides an in-memory Storager which is fully conformant with
+// the integration tests, it could be used as a test double and as an executable
+// specification of the expected behaviors.
+package memory
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// Type is the type for memory.
+const Type = "memory"
+
+// UserMetadataPairKey is the key of the pair which sets user metadata while writing.
+//
+// go-storage doesn't have a common pair for user metadata, so it's specific to memory.
+const UserMetadataPairKey = "user_metadata"
+
+// WithUserMetadata will apply user metadata to the written object.
+func WithUserMetadata(v map[string]string) types.Pair {
+	return types.Pair{Key: UserMetadataPairKey, Value: v}
+}
+
+// listPageSize is the max count of objects returned in one page while listing,
+// it mimics the page size used by most object storage services.
+const listPageSize = 1000
+
+// maxLinkDepth is the max depth of links to follow while reading.
+const maxLinkDepth = 16
+
+// Storage is the in-memory Storager.
+type Storage struct {
+	name    string
+	workDir string
+	// defaultPairs will be applied to every operation before the passed pairs.
+	defaultPairs []types.Pair
+
+	lock sync.Mutex
+	// objects is keyed by the absolute path of the object.
+	objects map[string]*object
+	// multiparts is keyed by the multipart id.
+	multiparts map[string]*multipart
+
+	types.UnimplementedStorager
+	types.UnimplementedAppender
+	types.UnimplementedCopier
+	types.UnimplementedDirer
+	types.UnimplementedLinker
+	types.UnimplementedMover
+	types.UnimplementedMultiparter
+}
+
+// object is an object stored in memory.
+type object struct {
+	mode         types.ObjectMode
+	content      []byte
+	contentType  string
+	contentMd5   string
+	etag         string
+	lastModified time.Time
+	linkTarget   string
+	userMetadata map[string]string
+	// appendable is true for append objects which are not committed yet.
+	appendable bool
+}
+
+// clone returns a deep copy of the object.
+func (o *object) clone() *object {
+	no := *o
+	no.content = append([]byte(nil), o.content...)
+	if o.userMetadata != nil {
+		no.userMetadata = make(map[string]string, len(o.userMetadata))
+		for k, v := range o.userMetadata {
+			no.userMetadata[k] = v
+		}
+	}
+	return &no
+}
+
+// NewStorager will create a new in-memory Storager.
+//
+// Only `name`, `work_dir`, `default_content_type` and `default_io_callback` pairs are
+// accepted, work_dir must be an absolute path.
+func NewStorager(pairs ...types.Pair) (types.Storager, error) {
+	return newStorage(pairs...)
+}
+
+func newStorage(pairs ...types.Pair) (store *Storage, err error) {
+	store = &Storage{
+		name:       Type,
+		workDir:    "/",
+		objects:    make(map[string]*object),
+		multiparts: make(map[string]*multipart),
+	}
+
+	for _, v := range pairs {
+		switch v.Key {
+		case "name":
+			store.name = v.Value.(string)
+		case "work_dir":
+			workDir := normalizePath(v.Value.(string))
+			if !strings.HasPrefix(workDir, "/") {
+				return nil, services.InitError{Op: "new_storager", Type: Type, Err: services.PairUnsupportedError{Pair: v}, Pairs: pairs}
+			}
+			store.workDir = cleanPath(workDir)
+		case "default_content_type":
+			store.defaultPairs = append(store.defaultPairs, types.Pair{Key: "content_type", Value: v.Value})
+		case "default_io_callback":
+			store.defaultPairs = append(store.defaultPairs, types.Pair{Key: "io_callback", Value: v.Value})
+		default:
+			return nil, services.InitError{Op: "new_storager", Type: Type, Err: services.PairUnsupportedError{Pair: v}, Pairs: pairs}
+		}
+	}
+	return store, nil
+}
+
+// String implements Storager.String
+func (s *Storage) String() string {
+	return fmt.Sprintf("Storager memory {Name: %s, WorkDir: %s}", s.name, s.workDir)
+}
+
+// Metadata implements Storager.Metadata
+func (s *Storage) Metadata(pairs ...types.Pair) (meta *types.StorageMeta) {
+	meta = types.NewStorageMeta()
+	meta.Name = s.name
+	meta.WorkDir = s.workDir
+	return meta
+}
+
+// Create implements Storager.Create
+func (s *Storage) Create(path string, pairs ...types.Pair) (o *types.Object) {
+	opt, _ := parseOptions(pairs)
+
+	o = types.NewObject(s, false)
+	o.ID = s.absPath(path)
+	o.Path = normalizePath(path)
+
+	switch {
+	case opt.hasMultipartID:
+		o.Mode = types.ModePart
+		o.SetMultipartID(opt.multipartID)
+	case opt.hasObjectMode:
+		o.Mode = opt.objectMode
+	default:
+		o.Mode = types.ModeRead
+	}
+	return o
+}
+
+// Delete implements Storager.Delete
+func (s *Storage) Delete(path string, pairs ...types.Pair) (err error) {
+	return s.DeleteWithContext(context.Background(), path, pairs...)
+}
+
+// DeleteWithContext implements Storager.DeleteWithContext
+func (s *Storage) DeleteWithContext(ctx context.Context, path string, pairs ...types.Pair) (err error) {
+	defer func() {
+		err = s.formatError("delete", err, path)
+	}()
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	opt, err := parseOptions(s.withDefaultPairs(pairs))
+	if err != nil {
+		return
+	}
+	key := s.absPath(path)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if opt.hasMultipartID {
+		if m, ok := s.multiparts[opt.multipartID]; ok && m.key == key {
+			delete(s.multiparts, opt.multipartID)
+		}
+		return nil
+	}
+
+	delete(s.objects, key)
+	return nil
+}
+
+// List implements Storager.List
+func (s *Storage) List(path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	return s.ListWithContext(context.Background(), path, pairs...)
+}
+
+// ListWithContext implements Storager.ListWithContext
+func (s *Storage) ListWithContext(ctx context.Context, path string, pairs ...types.Pair) (oi *types.ObjectIterator, err error) {
+	defer func() {
+		err = s.formatError("list", err, path)
+	}()
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	opt, err := parseOptions(s.withDefaultPairs(pairs))
+	if err != nil {
+		return
+	}
+	if !opt.hasListMode {
+		opt.listMode = types.ListModeDir
+	}
+
+	var objects []*types.Object
+	switch {
+	case opt.listMode.IsDir():
+		objects = s.listDir(path)
+	case opt.listMode.IsPrefix():
+		objects = s.listPrefix(path)
+	case opt.listMode.IsPart():
+		objects = s.listPart(path)
+	default:
+		return nil, services.ListModeInvalidError{Actual: opt.listMode}
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Path < objects[j].Path
+	})
+
+	input := &objectPageStatus{objects: objects}
+	return types.NewObjectIterator(ctx, s.nextObjectPage, input), nil
+}
+
+// listDir lists the objects and dirs directly under path without recursion.
+func (s *Storage) listDir(path string) []*types.Object {
+	dir := normalizePath(path)
+	if dir != "" && !strings.HasSuffix(dir, "/") {
+		dir += "/"
+	}
+	prefix := s.absPath(dir)
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	dirs := make(map[string]bool)
+	var objects []*types.Object
+	for key, v := range s.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		rest := key[len(prefix):]
+		if idx := strings.Index(rest, "/"); idx >= 0 {
+			dirs[dir+rest[:idx]] = true
+			continue
+		}
+		if v.mode.IsDir() {
+			dirs[dir+rest] = true
+			continue
+		}
+		objects = append(objects, s.newObject(dir+rest, key, v))
+	}
+
+	for p := range dirs {
+		o := types.NewObject(s, true)
+		o.ID = s.absPath(p)
+		o.Path = p
+		o.Mode = types.ModeDir
+		objects = append(objects, o)
+	}
+	return objects
+}
+
+// listPrefix lists all objects which have the prefix path recursively.
+func (s *Storage) listPrefix(path string) []*types.Object {
+	prefix := normalizePath(path)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var objects []*types.Object
+	for key, v := range s.objects {
+		rel, ok := s.relPath(key)
+		if !ok || !strings.HasPrefix(rel, prefix) || v.mode.IsDir() {
+			continue
+		}
+		objects = append(objects, s.newObject(rel, key, v))
+	}
+	return objects
+}
+
+type objectPageStatus struct {
+	objects []*types.Object
+	offset  int
+}
+
+func (i *objectPageStatus) ContinuationToken() string {
+	return strconv.Itoa(i.offset)
+}
+
+func (s *Storage) nextObjectPage(ctx context.Context, page *types.ObjectPage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	input := page.Status.(*objectPageStatus)
+
+	end := input.offset + listPageSize
+	if end > len(input.objects) {
+		end = len(input.objects)
+	}
+	page.Data = append(page.Data, input.objects[input.offset:end]...)
+	input.offset = end
+
+	if input.offset >= len(input.objects) {
+		return types.IterateDone
+	}
+	return nil
+}
+
+// Read implements Storager.Read
+func (s *Storage) Read(path string, w io.Writer, pairs ...types.Pair) (n int64, err error) {
+	return s.ReadWithContext(context.Background(), path, w, pairs...)
+}
+
+// ReadWithContext implements Storager.ReadWithContext
+func (s *Storage) ReadWithContext(ctx context.Context, path string, w io.Writer, pairs ...types.Pair) (n int64, err error) {
+	defer func() {
+		err = s.formatError("read", err, path)
+	}()
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	opt, err := parseOptions(s.withDefaultPairs(pairs))
+	if err != nil {
+		return
+	}
+
+	s.lock.Lock()
+	content, err := s.readContent(s.absPath(path))
+	s.lock.Unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	if opt.offset > int64(len(content)) {
+		opt.offset = int64(len(content))
+	}
+	content = content[opt.offset:]
+	if opt.hasSize && opt.size < int64(len(content)) {
+		content = content[:opt.size]
+	}
+
+	return copyWithContext(ctx, w, bytes.NewReader(content), int64(len(content)), opt.ioCallback)
+}
+
+// readContent returns the content of the object at key and follows links.
+//
+// Caller MUST hold the lock.
+func (s *Storage) readContent(key string) ([]byte, error) {
+	for i := 0; i < maxLinkDepth; i++ {
+		v, ok := s.objects[key]
+		if !ok {
+			return nil, services.ErrObjectNotExist
+		}
+		if v.mode.IsLink() {
+			key = v.linkTarget
+			continue
+		}
+		if v.mode.IsDir() {
+			return nil, services.ObjectModeInvalidError{Expected: types.ModeRead, Actual: v.mode}
+		}
+		return v.content, nil
+	}
+	return nil, services.ErrObjectNotExist
+}
+
+// Stat implements Storager.Stat
+func (s *Storage) Stat(path string, pairs ...types.Pair) (o *types.Object, err error) {
+	return s.StatWithContext(context.Background(), path, pairs...)
+}
+
+// StatWithContext implements Storager.StatWithContext
+func (s *Storage) StatWithContext(ctx context.Context, path string, pairs ...types.Pair) (o *types.Object, err error) {
+	defer func() {
+		err = s.formatError("stat", err, path)
+	}()
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	opt, err := parseOptions(s.withDefaultPairs(pairs))
+	if err != nil {
+		return
+	}
+	key := s.absPath(path)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if opt.hasMultipartID {
+		m, ok := s.multiparts[opt.multipartID]
+		if !ok || m.key != key {
+			return nil, services.ErrObjectNotExist
+		}
+		return s.newMultipartObject(normalizePath(path), m), nil
+	}
+
+	v, ok := s.objects[key]
+	if !ok {
+		return nil, services.ErrObjectNotExist
+	}
+	if opt.hasObjectMode && opt.objectMode.IsDir() != v.mode.IsDir() {
+		return nil, services.ObjectModeInvalidError{Expected: opt.objectMode, Actual: v.mode}
+	}
+	return s.newObject(normalizePath(path), key, v), nil
+}
+
+// Write implements Storager.Write
+func (s *Storage) Write(path string, r io.Reader, size int64, pairs ...types.Pair) (n int64, err error) {
+	return s.WriteWithContext(context.Background(), path, r, size, pairs...)
+}
+
+// WriteWithContext implements Storager.WriteWithContext
+func (s *Storage) WriteWithContext(ctx context.Context, path string, r io.Reader, size int64, pairs ...types.Pair) (n int64, err error) {
+	defer func() {
+		err = s.formatError("write", err, path)
+	}()
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	// A path ending with slash refers to a dir.
+	if strings.HasSuffix(normalizePath(path), "/") {
+		return 0, services.ObjectModeInvalidError{Expected: types.ModeRead, Actual: types.ModeDir}
+	}
+
+	opt, err := parseOptions(s.withDefaultPairs(pairs))
+	if err != nil {
+		return
+	}
+
+	content, err := readFull(ctx, r, size, opt.ioCallback)
+	if err != nil {
+		return 0, err
+	}
+
+	v := &object{
+		mode:         types.ModeRead,
+		content:      content,
+		contentType:  opt.contentType,
+		contentMd5:   opt.contentMd5,
+		userMetadata: opt.userMetadata,
+		etag:         etag(content),
+		lastModified: time.Now(),
+	}
+
+	key := s.absPath(path)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if ov, ok := s.objects[key]; ok && ov.mode.IsDir() {
+		return 0, services.ObjectModeInvalidError{Expected: types.ModeRead, Actual: ov.mode}
+	}
+	s.objects[key] = v
+	return size, nil
+}
+
+// newObject creates a types.Object from object.
+func (s *Storage) newObject(path, key string, v *object) *types.Object {
+	o := types.NewObject(s, true)
+	o.ID = key
+	o.Path = path
+	o.Mode = v.mode
+
+	if v.mode.IsDir() {
+		return o
+	}
+	if v.mode.IsLink() {
+		o.SetLinkTarget(v.linkTarget)
+		return o
+	}
+
+	o.SetContentLength(int64(len(v.content)))
+	o.SetEtag(v.etag)
+	o.SetLastModified(v.lastModified)
+	if v.contentType != "" {
+		o.SetContentType(v.contentType)
+	}
+	if v.contentMd5 != "" {
+		o.SetContentMd5(v.contentMd5)
+	}
+	if v.userMetadata != nil {
+		o.SetUserMetadata(v.userMetadata)
+	}
+	if v.mode.IsAppend() {
+		o.SetAppendOffset(int64(len(v.content)))
+	}
+	return o
+}
+
+func etag(content []byte) string {
+	sum := md5.Sum(content)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
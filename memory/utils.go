@@ -0,0 +1,195 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	pathpkg "path"
+	"strings"
+
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// bufferSize is the chunk size used while copying data.
+const bufferSize = 32 * 1024
+
+// options is the parsed pairs of an operation.
+type options struct {
+	hasOffset bool
+	offset    int64
+	hasSize   bool
+	size      int64
+
+	ioCallback func([]byte)
+
+	hasListMode bool
+	listMode    types.ListMode
+
+	hasObjectMode bool
+	objectMode    types.ObjectMode
+
+	hasMultipartID bool
+	multipartID    string
+
+	contentType  string
+	contentMd5   string
+	userMetadata map[string]string
+}
+
+// parseOptions parses pairs into options, PairUnsupportedError will be returned for
+// unknown pairs like services without loose_pair enabled.
+func parseOptions(pairs []types.Pair) (opt options, err error) {
+	for _, v := range pairs {
+		switch v.Key {
+		case "offset":
+			opt.hasOffset = true
+			opt.offset = v.Value.(int64)
+		case "size":
+			opt.hasSize = true
+			opt.size = v.Value.(int64)
+		case "io_callback":
+			opt.ioCallback = v.Value.(func([]byte))
+		case "list_mode":
+			opt.hasListMode = true
+			opt.listMode = v.Value.(types.ListMode)
+		case "object_mode":
+			opt.hasObjectMode = true
+			opt.objectMode = v.Value.(types.ObjectMode)
+		case "multipart_id":
+			opt.hasMultipartID = true
+			opt.multipartID = v.Value.(string)
+		case "content_type":
+			opt.contentType = v.Value.(string)
+		case "content_md5":
+			opt.contentMd5 = v.Value.(string)
+		case UserMetadataPairKey:
+			opt.userMetadata = v.Value.(map[string]string)
+		default:
+			return options{}, services.PairUnsupportedError{Pair: v}
+		}
+	}
+	return opt, nil
+}
+
+// withDefaultPairs returns pairs with the default pairs prepended, so that the passed
+// pairs take precedence.
+func (s *Storage) withDefaultPairs(pairs []types.Pair) []types.Pair {
+	if len(s.defaultPairs) == 0 {
+		return pairs
+	}
+	ps := make([]types.Pair, 0, len(s.defaultPairs)+len(pairs))
+	ps = append(ps, s.defaultPairs...)
+	return append(ps, pairs...)
+}
+
+// normalizePath converts path to the GSP-749 unified form which only uses slash.
+func normalizePath(path string) string {
+	return strings.ReplaceAll(path, "\\", "/")
+}
+
+// cleanPath cleans an absolute path.
+func cleanPath(path string) string {
+	return pathpkg.Clean("/" + path)
+}
+
+// absPath returns the absolute path of path, which is used as the key of objects.
+func (s *Storage) absPath(path string) string {
+	path = normalizePath(path)
+	if strings.HasPrefix(path, "/") {
+		return cleanPath(path)
+	}
+	return cleanPath(pathpkg.Join(s.workDir, path))
+}
+
+// relPath returns the path of key relative to work dir, false will be returned
+// if key is not under work dir.
+func (s *Storage) relPath(key string) (string, bool) {
+	if s.workDir == "/" {
+		return key[1:], true
+	}
+	if !strings.HasPrefix(key, s.workDir+"/") {
+		return "", false
+	}
+	return key[len(s.workDir)+1:], true
+}
+
+func (s *Storage) formatError(op string, err error, path ...string) error {
+	if err == nil {
+		return nil
+	}
+	// Don't wrap the error twice.
+	var se services.StorageError
+	if errors.As(err, &se) {
+		return err
+	}
+	return services.StorageError{
+		Op:       op,
+		Err:      err,
+		Storager: s,
+		Path:     path,
+	}
+}
+
+// readFull reads exactly size bytes from r, fn will be called for every chunk read.
+//
+// nil r is only allowed with 0 size, and an error will be returned if r has less
+// than size bytes.
+func readFull(ctx context.Context, r io.Reader, size int64, fn func([]byte)) ([]byte, error) {
+	if size < 0 {
+		return nil, fmt.Errorf("size %d is negative: %w", size, services.ErrRestrictionDissatisfied)
+	}
+	if r == nil {
+		if size != 0 {
+			return nil, fmt.Errorf("reader is nil but size is %d: %w", size, services.ErrRestrictionDissatisfied)
+		}
+		return []byte{}, nil
+	}
+
+	var buf bytes.Buffer
+	n, err := copyWithContext(ctx, &buf, r, size, fn)
+	if err != nil {
+		return nil, err
+	}
+	if n < size {
+		return nil, fmt.Errorf("read %d bytes, expected %d: %w", n, size, io.ErrUnexpectedEOF)
+	}
+	return buf.Bytes(), nil
+}
+
+// copyWithContext copies at most size bytes from r to w, ctx will be checked before
+// every chunk and fn will be called after every chunk written.
+func copyWithContext(ctx context.Context, w io.Writer, r io.Reader, size int64, fn func([]byte)) (n int64, err error) {
+	chunk := make([]byte, bufferSize)
+	for n < size {
+		if err = ctx.Err(); err != nil {
+			return n, err
+		}
+
+		want := size - n
+		if want > bufferSize {
+			want = bufferSize
+		}
+
+		rn, rerr := r.Read(chunk[:want])
+		if rn > 0 {
+			wn, werr := w.Write(chunk[:rn])
+			n += int64(wn)
+			if fn != nil {
+				fn(chunk[:wn])
+			}
+			if werr != nil {
+				return n, werr
+			}
+		}
+		if rerr == io.EOF {
+			return n, nil
+		}
+		if rerr != nil {
+			return n, rerr
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,229 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+func TestMetadata(t *testing.T, store types.Storager) {
+	TestMetadataWithProfile(t, store, Profile{})
+}
+
+func TestMetadataWithProfile(t *testing.T, store types.Storager, p Profile) {
+	setupRand(t)
+
+	Convey("Given a basic Storager", t, func() {
+		So(store, ShouldNotBeNil)
+
+		Convey("When Write a file with content type and content md5", func() {
+			dir := randUUID()
+			path := dir + "/" + randUUID()
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+
+			sum := md5.Sum(content)
+			contentMd5 := base64.StdEncoding.EncodeToString(sum[:])
+			contentType := "application/x-" + randUUID()
+
+			_, err := store.Write(path, bytes.NewReader(content), size,
+				pairs.WithContentType(contentType), pairs.WithContentMd5(contentMd5))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			Convey("Stat should return the same values", func() {
+				o, err := store.Stat(path)
+				So(err, ShouldBeNil)
+
+				ct, ok := o.GetContentType()
+				So(ok, ShouldBeTrue)
+				So(ct, ShouldEqual, contentType)
+
+				// Content md5 is not returned by all services.
+				if v, ok := o.GetContentMd5(); ok {
+					So(v, ShouldEqual, contentMd5)
+				}
+			})
+
+			Convey("List should return the same values", func() {
+				it, err := store.List(dir+"/", pairs.WithListMode(types.ListModeDir))
+				So(err, ShouldBeNil)
+
+				o, err := it.Next()
+				So(err, ShouldBeNil)
+				So(o.Path, ShouldEqual, path)
+
+				osize, ok := o.GetContentLength()
+				So(ok, ShouldBeTrue)
+				So(osize, ShouldEqual, size)
+
+				// Most services don't return content type and md5 while listing,
+				// but they must be the same if returned.
+				if v, ok := o.GetContentType(); ok {
+					So(v, ShouldEqual, contentType)
+				}
+				if v, ok := o.GetContentMd5(); ok {
+					So(v, ShouldEqual, contentMd5)
+				}
+			})
+
+			Convey("The etag and last modified should be populated", func() {
+				o, err := store.Stat(path)
+				So(err, ShouldBeNil)
+
+				etag, ok := o.GetEtag()
+				So(ok, ShouldBeTrue)
+				So(etag, ShouldNotBeEmpty)
+
+				lastModified, ok := o.GetLastModified()
+				So(ok, ShouldBeTrue)
+				So(lastModified.IsZero(), ShouldBeFalse)
+			})
+		})
+
+		Convey("When overwrite a file", func() {
+			path := randUUID()
+			size := randInt63n(4*1024*1024) + 1 // Max file size is 4MB
+
+			_, err := store.Write(path, io.LimitReader(randReader(), size), size)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			first, err := store.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = store.Write(path, io.LimitReader(randReader(), size), size)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			second, err := store.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			Convey("The etag should be changed", func() {
+				So(second.MustGetEtag(), ShouldNotEqual, first.MustGetEtag())
+			})
+
+			Convey("The last modified should not go backwards", func() {
+				So(second.MustGetLastModified().Before(first.MustGetLastModified()), ShouldBeFalse)
+			})
+		})
+
+		if p.UserMetadataPairKey == "" {
+			return
+		}
+
+		userMetadata := func() map[string]string {
+			// Most services only accept lower case keys.
+			return map[string]string{
+				"foo":    randUUID(),
+				"bar-id": randUUID(),
+			}
+		}
+
+		if c, ok := store.(types.Copier); ok {
+			Convey("When Copy a file with user metadata", func() {
+				src := randUUID()
+				dst := randUUID()
+				size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+				metadata := userMetadata()
+
+				_, err := store.Write(src, io.LimitReader(randReader(), size), size,
+					types.Pair{Key: p.UserMetadataPairKey, Value: metadata})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				defer func() {
+					err := store.Delete(src)
+					if err != nil {
+						t.Error(err)
+					}
+				}()
+
+				err = c.Copy(src, dst)
+
+				defer func() {
+					err := store.Delete(dst)
+					if err != nil {
+						t.Error(err)
+					}
+				}()
+
+				Convey("The user metadata should survive", func() {
+					So(err, ShouldBeNil)
+
+					o, err := store.Stat(dst)
+					So(err, ShouldBeNil)
+
+					v, ok := o.GetUserMetadata()
+					So(ok, ShouldBeTrue)
+					So(v, ShouldResemble, metadata)
+				})
+			})
+		}
+
+		if m, ok := store.(types.Mover); ok {
+			Convey("When Move a file with user metadata", func() {
+				src := randUUID()
+				dst := randUUID()
+				size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+				metadata := userMetadata()
+
+				_, err := store.Write(src, io.LimitReader(randReader(), size), size,
+					types.Pair{Key: p.UserMetadataPairKey, Value: metadata})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				err = m.Move(src, dst)
+
+				defer func() {
+					err := store.Delete(dst)
+					if err != nil {
+						t.Error(err)
+					}
+				}()
+
+				Convey("The user metadata should survive", func() {
+					So(err, ShouldBeNil)
+
+					o, err := store.Stat(dst)
+					So(err, ShouldBeNil)
+
+					v, ok := o.GetUserMetadata()
+					So(ok, ShouldBeTrue)
+					So(v, ShouldResemble, metadata)
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,436 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/beyondstorage/go-integration-test/v4/memory"
+	"github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/pkg/randbytes"
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+const (
+	// modelSequences is the number of random sequences run by TestModel.
+	modelSequences = 8
+	// modelSteps is the number of operations in every sequence.
+	modelSteps = 24
+	// modelMaxSize is the max size of content written by sequences.
+	modelMaxSize = 4096
+	// modelMaxShrinks is the max number of replays used to shrink a failed sequence.
+	modelMaxShrinks = 256
+)
+
+// Paths used by sequences are picked from small pools so that operations will collide
+// with each other. Pools are disjoint to avoid relying on service specific behaviors,
+// like writing to a path which is a dir or a link.
+var (
+	modelFiles = []string{"f0", "f1", "sub/f2"}
+	modelDirs  = []string{"d0"}
+	modelLinks = []string{"l0"}
+)
+
+type modelOpKind int
+
+const (
+	modelWrite modelOpKind = iota
+	modelRead
+	modelStat
+	modelDelete
+	modelList
+	modelCopy
+	modelMove
+	modelCreateDir
+	modelCreateLink
+)
+
+// modelOp is an operation in a sequence.
+//
+// Content is derived from seed so that the sequence could be replayed and printed.
+// offset and size of Read will be resolved against the model state while running.
+type modelOp struct {
+	kind   modelOpKind
+	path   string
+	target string
+	seed   int64
+	offset int64
+	size   int64
+}
+
+// modelRunner runs sequences on the store and a new model for every run.
+type modelRunner struct {
+	store   types.Storager
+	profile Profile
+	kinds   []modelOpKind
+}
+
+func newModelRunner(store types.Storager, p Profile) *modelRunner {
+	r := &modelRunner{
+		store:   store,
+		profile: p,
+		kinds:   []modelOpKind{modelWrite, modelRead, modelStat, modelDelete, modelList},
+	}
+	if _, ok := store.(types.Copier); ok {
+		r.kinds = append(r.kinds, modelCopy)
+	}
+	if _, ok := store.(types.Mover); ok {
+		r.kinds = append(r.kinds, modelMove)
+	}
+	if _, ok := store.(types.Direr); ok {
+		r.kinds = append(r.kinds, modelCreateDir)
+	}
+	if _, ok := store.(types.Linker); ok {
+		r.kinds = append(r.kinds, modelCreateLink)
+	}
+	return r
+}
+
+// generate returns a random sequence with n operations.
+func (r *modelRunner) generate(n int) []modelOp {
+	pick := func(pool []string) string {
+		return pool[randIntn(len(pool))]
+	}
+
+	// Written content must not be empty if the service doesn't support empty objects.
+	var minSize int64
+	if r.profile.NoEmptyObjects {
+		minSize = 1
+	}
+
+	ops := make([]modelOp, 0, n)
+	for i := 0; i < n; i++ {
+		op := modelOp{
+			kind:   r.kinds[randIntn(len(r.kinds))],
+			seed:   randInt63(),
+			offset: randInt63n(modelMaxSize),
+			size:   randInt63n(modelMaxSize) + minSize,
+		}
+
+		switch op.kind {
+		case modelCopy, modelMove:
+			// src and dst must be different.
+			idx := rand.New(rand.NewSource(op.seed)).Perm(len(modelFiles))
+			op.path, op.target = modelFiles[idx[0]], modelFiles[idx[1]]
+		case modelCreateDir:
+			op.path = pick(modelDirs)
+		case modelCreateLink:
+			op.path, op.target = pick(modelLinks), pick(modelFiles)
+		case modelDelete, modelStat:
+			all := append(append(append([]string{}, modelFiles...), modelDirs...), modelLinks...)
+			op.path = pick(all)
+		default:
+			op.path = pick(append(append([]string{}, modelFiles...), modelLinks...))
+		}
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+// run applies ops on both the store and a new model under a new dir, and returns the Go
+// code of the applied ops and the first mismatch.
+func (r *modelRunner) run(ops []modelOp) (code []string, err error) {
+	model, err := memory.NewStorager()
+	if err != nil {
+		return nil, err
+	}
+
+	base := randUUID()
+	defer r.cleanup(base)
+
+	code = append(code, fmt.Sprintf("base := %q", base))
+	for i, op := range ops {
+		c, expected, actual := r.apply(model, base, op)
+		code = append(code, c)
+
+		if expected != actual {
+			code = append(code, fmt.Sprintf("// expected: %s", expected), fmt.Sprintf("// actual:   %s", actual))
+			return code, fmt.Errorf("step %d %s: expected %s, actual %s", i, c, expected, actual)
+		}
+
+		expected, actual = describeState(model, base), describeState(r.store, base)
+		if expected != actual {
+			code = append(code, fmt.Sprintf("// expected state: %s", expected), fmt.Sprintf("// actual state:   %s", actual))
+			return code, fmt.Errorf("state after step %d %s: expected %s, actual %s", i, c, expected, actual)
+		}
+	}
+	return code, nil
+}
+
+// apply applies op on both model and store, and returns the Go code of op and the
+// observable results.
+func (r *modelRunner) apply(model types.Storager, base string, op modelOp) (code, expected, actual string) {
+	path := base + "/" + op.path
+	target := base + "/" + op.target
+
+	switch op.kind {
+	case modelWrite:
+		size := op.size
+		code = fmt.Sprintf("store.Write(base+%q, io.LimitReader(&randbytes.Rand{Source: rand.NewSource(%d)}, %d), %d)",
+			"/"+op.path, op.seed, size, size)
+		write := func(s types.Storager) string {
+			_, err := s.Write(path, io.LimitReader(&randbytes.Rand{Source: rand.NewSource(op.seed)}, size), size)
+			return describeError(err)
+		}
+		return code, write(model), write(r.store)
+	case modelRead:
+		var ps []types.Pair
+		code = fmt.Sprintf("store.Read(base+%q, ioutil.Discard)", "/"+op.path)
+
+		// Only read in range of the content, reading out of range is covered by other suites.
+		var buf bytes.Buffer
+		if n, err := model.Read(path, &buf); err == nil && n > 0 {
+			offset := op.offset % n
+			size := op.size%(n-offset) + 1
+			ps = append(ps, pairs.WithOffset(offset), pairs.WithSize(size))
+			code = fmt.Sprintf("store.Read(base+%q, ioutil.Discard, pairs.WithOffset(%d), pairs.WithSize(%d))",
+				"/"+op.path, offset, size)
+		}
+		read := func(s types.Storager) string {
+			var buf bytes.Buffer
+			_, err := s.Read(path, &buf, ps...)
+			if err != nil {
+				return describeError(err)
+			}
+			return describeContent(buf.Bytes())
+		}
+		return code, read(model), read(r.store)
+	case modelStat:
+		code = fmt.Sprintf("store.Stat(base+%q%s)", "/"+op.path, modeHintCode(op.path))
+		stat := func(s types.Storager) string {
+			o, err := s.Stat(path, modeHint(op.path)...)
+			if err != nil {
+				return describeError(err)
+			}
+			return describeObject(o)
+		}
+		return code, stat(model), stat(r.store)
+	case modelDelete:
+		code = fmt.Sprintf("store.Delete(base+%q%s)", "/"+op.path, modeHintCode(op.path))
+		del := func(s types.Storager) string {
+			return describeError(s.Delete(path, modeHint(op.path)...))
+		}
+		return code, del(model), del(r.store)
+	case modelList:
+		code = fmt.Sprintf("store.List(base+%q, pairs.WithListMode(types.ListModeDir))", "/")
+		return code, describeList(model, base), describeList(r.store, base)
+	case modelCopy:
+		code = fmt.Sprintf("store.(types.Copier).Copy(base+%q, base+%q)", "/"+op.path, "/"+op.target)
+		cp := func(s types.Storager) string {
+			return describeError(s.(types.Copier).Copy(path, target))
+		}
+		return code, cp(model), cp(r.store)
+	case modelMove:
+		code = fmt.Sprintf("store.(types.Mover).Move(base+%q, base+%q)", "/"+op.path, "/"+op.target)
+		mv := func(s types.Storager) string {
+			return describeError(s.(types.Mover).Move(path, target))
+		}
+		return code, mv(model), mv(r.store)
+	case modelCreateDir:
+		code = fmt.Sprintf("store.(types.Direr).CreateDir(base+%q)", "/"+op.path)
+		mkdir := func(s types.Storager) string {
+			_, err := s.(types.Direr).CreateDir(path)
+			return describeError(err)
+		}
+		return code, mkdir(model), mkdir(r.store)
+	case modelCreateLink:
+		code = fmt.Sprintf("store.(types.Linker).CreateLink(base+%q, base+%q)", "/"+op.path, "/"+op.target)
+		link := func(s types.Storager) string {
+			_, err := s.(types.Linker).CreateLink(path, target)
+			return describeError(err)
+		}
+		return code, link(model), link(r.store)
+	default:
+		panic(fmt.Sprintf("unknown model op %d", op.kind))
+	}
+}
+
+// cleanup deletes everything could be created by sequences under base.
+func (r *modelRunner) cleanup(base string) {
+	for _, pool := range [][]string{modelLinks, modelFiles, modelDirs, {"sub"}} {
+		for _, path := range pool {
+			_ = r.store.Delete(base+"/"+path, modeHint(path)...)
+		}
+	}
+	_ = r.store.Delete(base)
+}
+
+// shrink removes ops from a failed sequence one by one as long as it still fails,
+// and returns the minimal sequence found with its Go code and error.
+func (r *modelRunner) shrink(ops []modelOp, code []string, err error) ([]modelOp, []string, error) {
+	replays := 0
+	for shrunk := true; shrunk && replays < modelMaxShrinks; {
+		shrunk = false
+		for i := 0; i < len(ops) && replays < modelMaxShrinks; i++ {
+			candidate := append(append([]modelOp{}, ops[:i]...), ops[i+1:]...)
+
+			replays++
+			c, e := r.run(candidate)
+			if e == nil {
+				continue
+			}
+			ops, code, err = candidate, c, e
+			shrunk = true
+			i--
+		}
+	}
+	return ops, code, err
+}
+
+// isModelDir checks whether path is a dir created by CreateDir in sequences.
+func isModelDir(path string) bool {
+	for _, v := range modelDirs {
+		if v == path {
+			return true
+		}
+	}
+	return false
+}
+
+func modeHint(path string) []types.Pair {
+	if isModelDir(path) {
+		return []types.Pair{pairs.WithObjectMode(types.ModeDir)}
+	}
+	return nil
+}
+
+func modeHintCode(path string) string {
+	if len(modeHint(path)) > 0 {
+		return ", pairs.WithObjectMode(types.ModeDir)"
+	}
+	return ""
+}
+
+func describeError(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, services.ErrObjectNotExist):
+		return "object not exist"
+	case errors.Is(err, services.ErrObjectModeInvalid):
+		return "object mode invalid"
+	default:
+		return "error"
+	}
+}
+
+func describeContent(content []byte) string {
+	return fmt.Sprintf("%d bytes sha256:%x", len(content), sha256.Sum256(content))
+}
+
+func describeObject(o *types.Object) string {
+	switch {
+	case o.Mode.IsDir():
+		return "dir"
+	case o.Mode.IsLink():
+		return "link"
+	default:
+		size, _ := o.GetContentLength()
+		return fmt.Sprintf("file %d bytes", size)
+	}
+}
+
+func describeList(s types.Storager, base string) string {
+	it, err := s.List(base+"/", pairs.WithListMode(types.ListModeDir))
+	if err != nil {
+		return describeError(err)
+	}
+
+	var entries []string
+	for {
+		o, err := it.Next()
+		if err == types.IterateDone {
+			break
+		}
+		if err != nil {
+			return describeError(err)
+		}
+		// Some services return dirs with a trailing slash.
+		path := strings.TrimSuffix(strings.TrimPrefix(o.Path, base+"/"), "/")
+		if o.Mode.IsDir() && !isModelDir(path) {
+			// Implicit parent dirs like `sub` are kept by services with real dirs after
+			// their files are gone, but not by the model, so they are not compared.
+			// Files under them are still compared by describeState.
+			continue
+		}
+		entries = append(entries, fmt.Sprintf("%s: %s", path, describeObject(o)))
+	}
+	sort.Strings(entries)
+	return "[" + strings.Join(entries, ", ") + "]"
+}
+
+// describeState describes all paths in pools which could be observed via Stat and Read.
+func describeState(s types.Storager, base string) string {
+	var state []string
+	for _, pool := range [][]string{modelFiles, modelDirs, modelLinks} {
+		for _, path := range pool {
+			o, err := s.Stat(base+"/"+path, modeHint(path)...)
+			if err != nil {
+				state = append(state, fmt.Sprintf("%s: %s", path, describeError(err)))
+				continue
+			}
+			if o.Mode.IsDir() {
+				state = append(state, fmt.Sprintf("%s: %s", path, describeObject(o)))
+				continue
+			}
+
+			var buf bytes.Buffer
+			_, err = s.Read(base+"/"+path, &buf)
+			if err != nil {
+				state = append(state, fmt.Sprintf("%s: %s, read %s", path, describeObject(o), describeError(err)))
+				continue
+			}
+			state = append(state, fmt.Sprintf("%s: %s, %s", path, describeObject(o), describeContent(buf.Bytes())))
+		}
+	}
+	return "[" + strings.Join(state, ", ") + "]"
+}
+
+func TestModel(t *testing.T, store types.Storager) {
+	TestModelWithProfile(t, store, Profile{})
+}
+
+// TestModelWithProfile runs random operation sequences on both store and the in-memory
+// reference model, and compares the observable state after every step.
+//
+// A failed sequence will be shrunk to a minimal one, which is logged as Go code.
+func TestModelWithProfile(t *testing.T, store types.Storager, p Profile) {
+	setupRand(t)
+
+	Convey("Given a basic Storager", t, func() {
+		So(store, ShouldNotBeNil)
+
+		Convey("When run random operation sequences", func() {
+			r := newModelRunner(store, p)
+
+			var err error
+			for i := 0; i < modelSequences; i++ {
+				ops := r.generate(modelSteps)
+
+				var code []string
+				code, err = r.run(ops)
+				if err == nil {
+					continue
+				}
+
+				ops, code, err = r.shrink(ops, code, err)
+				t.Logf("sequence %d failed and shrunk to %d ops, reproduce with:\n\n%s\n",
+					i, len(ops), strings.Join(code, "\n"))
+				break
+			}
+
+			Convey("The observable state should match the model", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+}
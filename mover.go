@@ -6,27 +6,26 @@ import (
 	"errors"
 	"io"
 	"io/ioutil"
-	"math/rand"
 	"testing"
 
-	"github.com/google/uuid"
 	. "github.com/smartystreets/goconvey/convey"
 
 	"github.com/beyondstorage/go-storage/v4/pairs"
-	"github.com/beyondstorage/go-storage/v4/pkg/randbytes"
 	"github.com/beyondstorage/go-storage/v4/services"
 	"github.com/beyondstorage/go-storage/v4/types"
 )
 
 func TestMover(t *testing.T, store types.Storager) {
+	setupRand(t)
+
 	Convey("Given a basic Storager", t, func() {
 		m, ok := store.(types.Mover)
 		So(ok, ShouldBeTrue)
 
 		Convey("When Move a file", func() {
-			size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			content, _ := ioutil.ReadAll(io.LimitReader(randbytes.NewRand(), size))
-			src := uuid.New().String()
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+			src := randUUID()
 
 			_, err := store.Write(src, bytes.NewReader(content), size)
 			if err != nil {
@@ -40,7 +39,7 @@ func TestMover(t *testing.T, store types.Storager) {
 				}
 			}()
 
-			dst := uuid.New().String()
+			dst := randUUID()
 			err = m.Move(src, dst)
 
 			defer func() {
@@ -79,9 +78,9 @@ func TestMover(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When Move to an existing file", func() {
-			srcSize := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			content, _ := ioutil.ReadAll(io.LimitReader(randbytes.NewRand(), srcSize))
-			src := uuid.New().String()
+			srcSize := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), srcSize))
+			src := randUUID()
 
 			_, err := store.Write(src, bytes.NewReader(content), srcSize)
 			if err != nil {
@@ -95,9 +94,9 @@ func TestMover(t *testing.T, store types.Storager) {
 				}
 			}()
 
-			dstSize := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			r := io.LimitReader(randbytes.NewRand(), dstSize)
-			dst := uuid.New().String()
+			dstSize := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			r := io.LimitReader(randReader(), dstSize)
+			dst := randUUID()
 
 			_, err = store.Write(dst, r, dstSize)
 			if err != nil {
@@ -143,6 +142,8 @@ func TestMover(t *testing.T, store types.Storager) {
 }
 
 func TestMoverWithDir(t *testing.T, store types.Storager) {
+	setupRand(t)
+
 	Convey("Given a basic Storager", t, func() {
 		m, ok := store.(types.Mover)
 		So(ok, ShouldBeTrue)
@@ -151,9 +152,9 @@ func TestMoverWithDir(t *testing.T, store types.Storager) {
 
 		Convey("When Move to an existing dir", func() {
 
-			srcSize := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			r := io.LimitReader(randbytes.NewRand(), srcSize)
-			src := uuid.New().String()
+			srcSize := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			r := io.LimitReader(randReader(), srcSize)
+			src := randUUID()
 
 			_, err := store.Write(src, r, srcSize)
 			if err != nil {
@@ -167,7 +168,7 @@ func TestMoverWithDir(t *testing.T, store types.Storager) {
 				}
 			}()
 
-			dst := uuid.New().String()
+			dst := randUUID()
 			_, err = d.CreateDir(dst)
 			if err != nil {
 				t.Fatal(err)
@@ -189,6 +190,8 @@ func TestMoverWithDir(t *testing.T, store types.Storager) {
 }
 
 func TestMoverWithVirtualDir(t *testing.T, store types.Storager) {
+	setupRand(t)
+
 	Convey("Given a basic Storager", t, func() {
 		m, ok := store.(types.Mover)
 		So(ok, ShouldBeTrue)
@@ -197,9 +200,9 @@ func TestMoverWithVirtualDir(t *testing.T, store types.Storager) {
 
 		Convey("When Move to an existing dir", func() {
 
-			srcSize := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			r := io.LimitReader(randbytes.NewRand(), srcSize)
-			src := uuid.New().String()
+			srcSize := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			r := io.LimitReader(randReader(), srcSize)
+			src := randUUID()
 
 			_, err := store.Write(src, r, srcSize)
 			if err != nil {
@@ -213,7 +216,7 @@ func TestMoverWithVirtualDir(t *testing.T, store types.Storager) {
 				}
 			}()
 
-			dst := uuid.New().String()
+			dst := randUUID()
 			_, err = d.CreateDir(dst)
 			if err != nil {
 				t.Fatal(err)
@@ -261,3 +264,18 @@ func TestMoverWithVirtualDir(t *testing.T, store types.Storager) {
 		})
 	})
 }
+
+// TestMoverWithProfile will run TestMover and the dir tests which match
+// the dir behavior in p if store is also a Direr.
+func TestMoverWithProfile(t *testing.T, store types.Storager, p Profile) {
+	TestMover(t, store)
+
+	if _, ok := store.(types.Direr); !ok {
+		return
+	}
+	if p.VirtualDir {
+		TestMoverWithVirtualDir(t, store)
+	} else {
+		TestMoverWithDir(t, store)
+	}
+}
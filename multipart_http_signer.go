@@ -4,26 +4,25 @@ import (
 	"bytes"
 	"io"
 	"io/ioutil"
-	"math/rand"
 	"net/http"
 	"testing"
 	"time"
 
-	"github.com/google/uuid"
 	. "github.com/smartystreets/goconvey/convey"
 
 	"github.com/beyondstorage/go-storage/v4/pairs"
-	"github.com/beyondstorage/go-storage/v4/pkg/randbytes"
 	"github.com/beyondstorage/go-storage/v4/types"
 )
 
 func TestMultipartHTTPSigner(t *testing.T, store types.Storager) {
+	setupRand(t)
+
 	Convey("Given a basic Storager", t, func() {
 		signer, ok := store.(types.MultipartHTTPSigner)
 		So(ok, ShouldBeTrue)
 
 		Convey("When CreateMultipart via QuerySignHTTPCreateMultipart", func() {
-			path := uuid.New().String()
+			path := randUUID()
 			req, err := signer.QuerySignHTTPCreateMultipart(path, time.Duration(time.Hour))
 
 			Convey("The error should be nil", func() {
@@ -70,7 +69,7 @@ func TestMultipartHTTPSigner(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When WriteMultipart via QuerySignHTTPWriteMultipart", func() {
-			path := uuid.New().String()
+			path := randUUID()
 			o, err := store.(types.Multiparter).CreateMultipart(path)
 			if err != nil {
 				t.Error(err)
@@ -83,8 +82,8 @@ func TestMultipartHTTPSigner(t *testing.T, store types.Storager) {
 				}
 			}()
 
-			size := rand.Int63n(4 * 1024 * 1024)
-			content, err := ioutil.ReadAll(io.LimitReader(randbytes.NewRand(), size))
+			size := randInt63n(4 * 1024 * 1024)
+			content, err := ioutil.ReadAll(io.LimitReader(randReader(), size))
 			if err != nil {
 				t.Error(err)
 			}
@@ -117,7 +116,7 @@ func TestMultipartHTTPSigner(t *testing.T, store types.Storager) {
 			mu, ok := store.(types.Multiparter)
 			So(ok, ShouldBeTrue)
 
-			path := uuid.New().String()
+			path := randUUID()
 			o, err := mu.CreateMultipart(path)
 			if err != nil {
 				t.Error(err)
@@ -130,9 +129,9 @@ func TestMultipartHTTPSigner(t *testing.T, store types.Storager) {
 				}
 			}()
 
-			size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			partNumber := rand.Intn(1000)        // Choose a random part number from [0, 1000)
-			r := io.LimitReader(randbytes.NewRand(), size)
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			partNumber := randIntn(1000)        // Choose a random part number from [0, 1000)
+			r := io.LimitReader(randReader(), size)
 
 			_, _, err = mu.WriteMultipart(o, r, size, partNumber)
 			if err != nil {
@@ -160,7 +159,7 @@ func TestMultipartHTTPSigner(t *testing.T, store types.Storager) {
 			mu, ok := store.(types.Multiparter)
 			So(ok, ShouldBeTrue)
 
-			path := uuid.New().String()
+			path := randUUID()
 			o, err := mu.CreateMultipart(path)
 			if err != nil {
 				t.Error(err)
@@ -173,10 +172,10 @@ func TestMultipartHTTPSigner(t *testing.T, store types.Storager) {
 				}
 			}()
 
-			size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
 			// Set 0 to `partNumber` here as the part numbers must be continuous for `CompleteMultipartUpload` in `cos` which is different with other storages.
 			partNumber := 0
-			r := io.LimitReader(randbytes.NewRand(), size)
+			r := io.LimitReader(randReader(), size)
 
 			_, part, err := mu.WriteMultipart(o, r, size, partNumber)
 			if err != nil {
@@ -1,25 +1,133 @@
 package tests
 
 import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
 	"io"
-	"math/rand"
+	"sort"
+	"sync"
 	"testing"
 
-	"github.com/google/uuid"
 	. "github.com/smartystreets/goconvey/convey"
 
 	"github.com/beyondstorage/go-storage/v4/pairs"
-	"github.com/beyondstorage/go-storage/v4/pkg/randbytes"
+	"github.com/beyondstorage/go-storage/v4/services"
 	"github.com/beyondstorage/go-storage/v4/types"
 )
 
+// multipartParts is the number of parts uploaded in the many parts scenarios.
+const multipartParts = 24
+
+// uploadPart is a part to upload, its content is generated from seed.
+type uploadPart struct {
+	index int
+	size  int64
+	seed  int64
+}
+
+// uploadParts returns n parts with indexes allowed by p, every part is larger than
+// the min part size except the last one. n will be capped at the max part number.
+func uploadParts(p Profile, n int) []uploadPart {
+	if max := p.maxPartNumber(); n > max {
+		n = max
+	}
+
+	indexes := make([]int, 0, n)
+	if !p.DiscontinuousPartNumbers {
+		for i := 0; i < n; i++ {
+			indexes = append(indexes, i)
+		}
+	} else {
+		seen := make(map[int]bool)
+		for len(indexes) < n {
+			idx := randIntn(p.maxPartNumber())
+			if !seen[idx] {
+				seen[idx] = true
+				indexes = append(indexes, idx)
+			}
+		}
+		sort.Ints(indexes)
+	}
+
+	parts := make([]uploadPart, 0, n)
+	for i, idx := range indexes {
+		size := p.minPartSize() + randInt63n(1024*1024)
+		if i == n-1 {
+			size = randInt63n(p.minPartSize()+1) + 1
+		}
+		parts = append(parts, uploadPart{index: idx, size: size, seed: randInt63()})
+	}
+	return parts
+}
+
+// writeParts writes parts concurrently in random order, returned parts are in the same
+// order as input.
+func writeParts(m types.Multiparter, o *types.Object, parts []uploadPart) ([]*types.Part, error) {
+	order := randPerm(len(parts))
+
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for _, i := range order {
+			ch <- i
+		}
+	}()
+
+	written := make([]*types.Part, len(parts))
+	var errs errorList
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range ch {
+				v := parts[i]
+				_, part, err := m.WriteMultipart(o, io.LimitReader(seededReader(v.seed), v.size), v.size, v.index)
+				if err != nil {
+					errs.Add("write part %d: %w", v.index, err)
+					continue
+				}
+				written[i] = part
+			}
+		}()
+	}
+	wg.Wait()
+
+	if e := errs.Errors(); len(e) > 0 {
+		return nil, fmt.Errorf("%v", e)
+	}
+	return written, nil
+}
+
+// partsSum returns the sha256 of the concatenation of parts.
+func partsSum(parts []uploadPart) []byte {
+	h := sha256.New()
+	for _, v := range parts {
+		_, _ = io.Copy(h, io.LimitReader(seededReader(v.seed), v.size))
+	}
+	return h.Sum(nil)
+}
+
 func TestMultiparter(t *testing.T, store types.Storager) {
+	testMultiparter(t, store, Profile{}, false)
+}
+
+// TestMultiparterWithProfile also runs the many parts scenarios, which upload about
+// 2*multipartParts parts larger than the min part size in p.
+func TestMultiparterWithProfile(t *testing.T, store types.Storager, p Profile) {
+	testMultiparter(t, store, p, true)
+}
+
+func testMultiparter(t *testing.T, store types.Storager, p Profile, manyParts bool) {
+	setupRand(t)
+
 	Convey("Given a basic Storager", t, func() {
 		m, ok := store.(types.Multiparter)
 		So(ok, ShouldBeTrue)
 
 		Convey("When CreateMultipart", func() {
-			path := uuid.New().String()
+			path := randUUID()
 			o, err := m.CreateMultipart(path)
 
 			Convey("The first returned error should be nil", func() {
@@ -61,7 +169,7 @@ func TestMultiparter(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When Delete with multipart id", func() {
-			path := uuid.New().String()
+			path := randUUID()
 			o, err := m.CreateMultipart(path)
 			if err != nil {
 				t.Error(err)
@@ -79,7 +187,7 @@ func TestMultiparter(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When Stat with multipart id", func() {
-			path := uuid.New().String()
+			path := randUUID()
 			o, err := m.CreateMultipart(path)
 			if err != nil {
 				t.Error(err)
@@ -117,7 +225,7 @@ func TestMultiparter(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When Create with multipart id", func() {
-			path := uuid.New().String()
+			path := randUUID()
 			o, err := m.CreateMultipart(path)
 			if err != nil {
 				t.Error(err)
@@ -150,7 +258,7 @@ func TestMultiparter(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When WriteMultipart", func() {
-			path := uuid.New().String()
+			path := randUUID()
 			o, err := m.CreateMultipart(path)
 			if err != nil {
 				t.Error(err)
@@ -163,8 +271,8 @@ func TestMultiparter(t *testing.T, store types.Storager) {
 				}
 			}()
 
-			size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			r := io.LimitReader(randbytes.NewRand(), size)
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			r := io.LimitReader(randReader(), size)
 
 			n, part, err := m.WriteMultipart(o, r, size, 0)
 
@@ -182,7 +290,7 @@ func TestMultiparter(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When ListMultiPart", func() {
-			path := uuid.New().String()
+			path := randUUID()
 			o, err := m.CreateMultipart(path)
 			if err != nil {
 				t.Error(err)
@@ -195,9 +303,9 @@ func TestMultiparter(t *testing.T, store types.Storager) {
 				}
 			}()
 
-			size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			partNumber := rand.Intn(1000)        // Choose a random part number from [0, 1000)
-			r := io.LimitReader(randbytes.NewRand(), size)
+			size := randInt63n(4 * 1024 * 1024)       // Max file size is 4MB
+			partNumber := randIntn(p.maxPartNumber()) // Choose a random part number from [0, MaxPartNumber)
+			r := io.LimitReader(randReader(), size)
 
 			_, _, err = m.WriteMultipart(o, r, size, partNumber)
 			if err != nil {
@@ -223,7 +331,7 @@ func TestMultiparter(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When List with part type", func() {
-			path := uuid.New().String()
+			path := randUUID()
 			o, err := m.CreateMultipart(path)
 			if err != nil {
 				t.Error(err)
@@ -236,9 +344,9 @@ func TestMultiparter(t *testing.T, store types.Storager) {
 				}
 			}()
 
-			size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			partNumber := rand.Intn(1000)        // Choose a random part number from [0, 1000)
-			r := io.LimitReader(randbytes.NewRand(), size)
+			size := randInt63n(4 * 1024 * 1024)       // Max file size is 4MB
+			partNumber := randIntn(p.maxPartNumber()) // Choose a random part number from [0, MaxPartNumber)
+			r := io.LimitReader(randReader(), size)
 
 			_, _, err = m.WriteMultipart(o, r, size, partNumber)
 			if err != nil {
@@ -270,7 +378,7 @@ func TestMultiparter(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When CompletePart", func() {
-			path := uuid.New().String()
+			path := randUUID()
 			o, err := m.CreateMultipart(path)
 			if err != nil {
 				t.Error(err)
@@ -283,10 +391,13 @@ func TestMultiparter(t *testing.T, store types.Storager) {
 				}
 			}()
 
-			size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			// Set 0 to `partNumber` here as the part numbers must be continuous for `CompleteMultipartUpload` in `cos` which is different with other storages.
-			partNumber := 0
-			r := io.LimitReader(randbytes.NewRand(), size)
+			size := randInt63n(4 * 1024 * 1024)       // Max file size is 4MB
+			partNumber := randIntn(p.maxPartNumber()) // Choose a random part number from [0, MaxPartNumber)
+			if !p.DiscontinuousPartNumbers {
+				// The part numbers must be continuous for `CompleteMultipartUpload` in services like `cos`.
+				partNumber = 0
+			}
+			r := io.LimitReader(randReader(), size)
 
 			_, part, err := m.WriteMultipart(o, r, size, partNumber)
 			if err != nil {
@@ -307,5 +418,267 @@ func TestMultiparter(t *testing.T, store types.Storager) {
 				So(ro.Mode.IsPart(), ShouldBeFalse)
 			})
 		})
+
+		Convey("When WriteMultipart with a deleted multipart id", func() {
+			path := randUUID()
+			o, err := m.CreateMultipart(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = store.Delete(path, pairs.WithMultipartID(o.MustGetMultipartID()))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			r := io.LimitReader(randReader(), size)
+
+			_, _, err = m.WriteMultipart(o, r, size, 0)
+
+			Convey("The error should be a StorageError", func() {
+				So(err, ShouldNotBeNil)
+
+				var se services.StorageError
+				So(errors.As(err, &se), ShouldBeTrue)
+			})
+		})
+
+		Convey("When CompleteMultipart with invalid parts", func() {
+			path := randUUID()
+			o, err := m.CreateMultipart(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				err := store.Delete(path, pairs.WithMultipartID(o.MustGetMultipartID()))
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			// The part is not smaller than the min part size, so that it's valid to be
+			// followed by other parts, and the unknown part is the only invalid one.
+			size := p.minPartSize() + randInt63n(1024*1024)
+			r := io.LimitReader(randReader(), size)
+
+			_, part, err := m.WriteMultipart(o, r, size, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			Convey("CompleteMultipart with empty parts should return error", func() {
+				err := m.CompleteMultipart(o, []*types.Part{})
+
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("CompleteMultipart with unknown parts should return error", func() {
+				// Part 1 is never uploaded.
+				err := m.CompleteMultipart(o, []*types.Part{part, {Index: 1, Size: size, ETag: part.ETag}})
+
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Stat should get nil Object and ObjectNotFound error", func() {
+				ro, err := store.Stat(path)
+
+				So(errors.Is(err, services.ErrObjectNotExist), ShouldBeTrue)
+				So(ro, ShouldBeNil)
+			})
+		})
+
+		Convey("When Stat with a bogus multipart id", func() {
+			path := randUUID()
+			o, err := m.CreateMultipart(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				err := store.Delete(path, pairs.WithMultipartID(o.MustGetMultipartID()))
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			mo, err := store.Stat(path, pairs.WithMultipartID(randUUID()))
+
+			Convey("Stat should get nil Object and ObjectNotFound error", func() {
+				So(errors.Is(err, services.ErrObjectNotExist), ShouldBeTrue)
+				So(mo, ShouldBeNil)
+			})
+		})
+
+		Convey("When List with part type after Delete with multipart id", func() {
+			path := randUUID()
+			o, err := m.CreateMultipart(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			multipartID := o.MustGetMultipartID()
+
+			err = store.Delete(path, pairs.WithMultipartID(multipartID))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			it, err := store.List("", pairs.WithListMode(types.ListModePart))
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The deleted upload should not be listed", func() {
+				found := false
+				for {
+					mo, err := it.Next()
+					if err == types.IterateDone {
+						break
+					}
+					So(err, ShouldBeNil)
+
+					if mid, ok := mo.GetMultipartID(); ok && mid == multipartID {
+						found = true
+					}
+				}
+				So(found, ShouldBeFalse)
+			})
+		})
+
+		if !manyParts {
+			return
+		}
+
+		Convey("When CompleteMultipart with many parts written out of order", func() {
+			path := randUUID()
+			o, err := m.CreateMultipart(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				// The upload is left behind if CompleteMultipart failed.
+				err := store.Delete(path, pairs.WithMultipartID(o.MustGetMultipartID()))
+				if err != nil {
+					t.Error(err)
+				}
+				err = store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			parts := uploadParts(p, multipartParts)
+			written, err := writeParts(m, o, parts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Multiple leaves will upload all parts again, so check them at once.
+			Convey("The parts should be listed and the content should be the concatenation of parts", func() {
+				it, err := m.ListMultipart(o)
+				So(err, ShouldBeNil)
+
+				listed := make(map[int]int64)
+				for {
+					part, err := it.Next()
+					if err == types.IterateDone {
+						break
+					}
+					So(err, ShouldBeNil)
+					listed[part.Index] = part.Size
+				}
+
+				So(listed, ShouldHaveLength, len(parts))
+				for _, v := range parts {
+					So(listed, ShouldContainKey, v.index)
+					So(listed[v.index], ShouldEqual, v.size)
+				}
+
+				err = m.CompleteMultipart(o, written)
+				So(err, ShouldBeNil)
+
+				var size int64
+				for _, v := range parts {
+					size += v.size
+				}
+
+				h := sha256.New()
+				n, err := store.Read(path, h)
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, size)
+				So(h.Sum(nil), ShouldResemble, partsSum(parts))
+			})
+		})
+
+		Convey("When CompleteMultipart with a subset of written parts", func() {
+			parts := uploadParts(p, multipartParts)
+			if len(parts) < 2 {
+				t.Logf("MaxPartNumber %d is too small to complete with a subset of parts", p.maxPartNumber())
+				return
+			}
+
+			path := randUUID()
+			o, err := m.CreateMultipart(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				// The upload is left behind if CompleteMultipart failed.
+				err := store.Delete(path, pairs.WithMultipartID(o.MustGetMultipartID()))
+				if err != nil {
+					t.Error(err)
+				}
+				err = store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			written, err := writeParts(m, o, parts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Select k parts in [1, len(parts)-1], which are a prefix of parts while
+			// part numbers must be continuous, otherwise random parts in order.
+			k := randIntn(len(parts)-1) + 1
+			indexes := make([]int, k)
+			for i := range indexes {
+				indexes[i] = i
+			}
+			if p.DiscontinuousPartNumbers {
+				indexes = randPerm(len(parts))[:k]
+				sort.Ints(indexes)
+			}
+
+			var selected []uploadPart
+			var selectedParts []*types.Part
+			for _, i := range indexes {
+				selected = append(selected, parts[i])
+				selectedParts = append(selectedParts, written[i])
+			}
+
+			err = m.CompleteMultipart(o, selectedParts)
+
+			Convey("The content should be the concatenation of selected parts", func() {
+				So(err, ShouldBeNil)
+
+				var size int64
+				for _, v := range selected {
+					size += v.size
+				}
+
+				h := sha256.New()
+				n, err := store.Read(path, h)
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, size)
+				So(h.Sum(nil), ShouldResemble, partsSum(selected))
+			})
+		})
 	})
 }
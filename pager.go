@@ -0,0 +1,294 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// pageSize is the page alignment used by page blob services.
+const pageSize = 512
+
+func TestPager(t *testing.T, store types.Storager) {
+	setupRand(t)
+
+	Convey("Given a basic Storager", t, func() {
+		p, ok := store.(types.Pager)
+		So(ok, ShouldBeTrue)
+
+		Convey("When CreatePage", func() {
+			path := randUUID()
+			o, err := p.CreatePage(path)
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			Convey("The first returned error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			o, err = p.CreatePage(path)
+
+			Convey("The second returned error also should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The Object Path should equal to the input path", func() {
+				So(o.Path, ShouldEqual, path)
+			})
+
+			Convey("The Object Mode should be page", func() {
+				// Page object's mode must be Page.
+				So(o.Mode.IsPage(), ShouldBeTrue)
+			})
+		})
+
+		Convey("When Stat a page object", func() {
+			path := randUUID()
+			_, err := p.CreatePage(path)
+			if err != nil {
+				t.Error(err)
+			}
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			o, err := store.Stat(path)
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+				So(o, ShouldNotBeNil)
+			})
+
+			Convey("The Object Mode should be page", func() {
+				So(o.Mode.IsPage(), ShouldBeTrue)
+			})
+		})
+
+		Convey("When WritePage at an aligned offset", func() {
+			path := randUUID()
+			o, err := p.CreatePage(path)
+			if err != nil {
+				t.Error(err)
+			}
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			size := (randInt63n(1024) + 1) * pageSize                   // Max page size is 512KB
+			offset := (randInt63n(4*1024*1024/pageSize) + 1) * pageSize // Max offset is 4MB
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+
+			n, err := p.WritePage(o, bytes.NewReader(content), size, offset)
+
+			Convey("WritePage error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("WritePage size should be equal to n", func() {
+				So(n, ShouldEqual, size)
+			})
+
+			Convey("Read with offset and size should get the page content", func() {
+				var buf bytes.Buffer
+				n, err := store.Read(path, &buf, pairs.WithOffset(offset), pairs.WithSize(size))
+
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, size)
+				So(sha256.Sum256(buf.Bytes()), ShouldResemble, sha256.Sum256(content))
+			})
+
+			Convey("Read before the offset should get zeros", func() {
+				var buf bytes.Buffer
+				n, err := store.Read(path, &buf, pairs.WithOffset(0), pairs.WithSize(offset))
+
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, offset)
+				So(buf.Bytes(), ShouldResemble, make([]byte, offset))
+			})
+		})
+
+		Convey("When WritePage at an unaligned offset", func() {
+			path := randUUID()
+			o, err := p.CreatePage(path)
+			if err != nil {
+				t.Error(err)
+			}
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			size := randInt63n(512*1024) + 1               // Max page size is 512KB
+			offset := randInt63n(4*1024*1024-pageSize) | 1 // Max offset is 4MB, and it's always odd
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+
+			n, err := p.WritePage(o, bytes.NewReader(content), size, offset)
+
+			// Page blob services like azblob require pages to be aligned to pageSize,
+			// they should reject the page with a services error code.
+			if err != nil {
+				Convey("The error should be ErrRestrictionDissatisfied or ErrCapabilityInsufficient", func() {
+					So(errors.Is(err, services.ErrRestrictionDissatisfied) ||
+						errors.Is(err, services.ErrCapabilityInsufficient), ShouldBeTrue)
+				})
+				return
+			}
+
+			Convey("WritePage size should be equal to n", func() {
+				So(n, ShouldEqual, size)
+			})
+
+			Convey("Read with offset and size should get the page content", func() {
+				var buf bytes.Buffer
+				n, err := store.Read(path, &buf, pairs.WithOffset(offset), pairs.WithSize(size))
+
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, size)
+				So(sha256.Sum256(buf.Bytes()), ShouldResemble, sha256.Sum256(content))
+			})
+		})
+
+		Convey("When WritePage with a sparse region", func() {
+			path := randUUID()
+			o, err := p.CreatePage(path)
+			if err != nil {
+				t.Error(err)
+			}
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			size := (randInt63n(1024) + 1) * pageSize // Max page size is 512KB
+			gap := (randInt63n(1024) + 1) * pageSize  // Max gap size is 512KB
+			first, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+			second, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+
+			_, err = p.WritePage(o, bytes.NewReader(first), size, 0)
+			if err != nil {
+				t.Error(err)
+			}
+
+			_, err = p.WritePage(o, bytes.NewReader(second), size, size+gap)
+			if err != nil {
+				t.Error(err)
+			}
+
+			Convey("Read the sparse region should get zeros", func() {
+				var buf bytes.Buffer
+				n, err := store.Read(path, &buf, pairs.WithOffset(size), pairs.WithSize(gap))
+
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, gap)
+				So(buf.Bytes(), ShouldResemble, make([]byte, gap))
+			})
+
+			Convey("Read the whole object should get all pages", func() {
+				var buf bytes.Buffer
+				n, err := store.Read(path, &buf, pairs.WithSize(size*2+gap))
+
+				expected := append(append(append([]byte{}, first...), make([]byte, gap)...), second...)
+
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, size*2+gap)
+				So(sha256.Sum256(buf.Bytes()), ShouldResemble, sha256.Sum256(expected))
+			})
+		})
+
+		Convey("When WritePage to overwrite an existing page", func() {
+			path := randUUID()
+			o, err := p.CreatePage(path)
+			if err != nil {
+				t.Error(err)
+			}
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			size := (randInt63n(1024) + 2) * pageSize // Max page size is 512KB
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+
+			_, err = p.WritePage(o, bytes.NewReader(content), size, 0)
+			if err != nil {
+				t.Error(err)
+			}
+
+			// Overwrite a random aligned range inside the existing pages.
+			offset := randInt63n(size/pageSize-1) * pageSize
+			length := (randInt63n((size-offset)/pageSize) + 1) * pageSize
+			patch, _ := ioutil.ReadAll(io.LimitReader(randReader(), length))
+
+			n, err := p.WritePage(o, bytes.NewReader(patch), length, offset)
+
+			Convey("WritePage error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("WritePage size should be equal to n", func() {
+				So(n, ShouldEqual, length)
+			})
+
+			Convey("The content should be overwritten in place", func() {
+				expected := append([]byte{}, content...)
+				copy(expected[offset:], patch)
+
+				var buf bytes.Buffer
+				n, err := store.Read(path, &buf, pairs.WithSize(size))
+
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, size)
+				So(sha256.Sum256(buf.Bytes()), ShouldResemble, sha256.Sum256(expected))
+			})
+		})
+
+		Convey("When Delete", func() {
+			path := randUUID()
+			_, err := p.CreatePage(path)
+			if err != nil {
+				t.Error(err)
+			}
+
+			err = store.Delete(path)
+			Convey("The first returned error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			err = store.Delete(path)
+			Convey("The second returned error also should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,268 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// TestUnsupportedPairs passes a pair which is not supported by any service to every
+// operation, services should either ignore it in all operations like loose_pair is
+// enabled, or reject it with PairUnsupportedError in all operations.
+func TestUnsupportedPairs(t *testing.T, store types.Storager) {
+	setupRand(t)
+
+	Convey("Given a basic Storager", t, func() {
+		So(store, ShouldNotBeNil)
+
+		Convey("When pass an unsupported pair to every operation", func() {
+			dir := randUUID()
+			path := dir + "/" + randUUID()
+			size := randInt63n(4*1024*1024) + 1 // Max file size is 4MB
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+
+			_, err := store.Write(path, bytes.NewReader(content), size)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			pair := types.Pair{Key: unsupportedPairKey, Value: randUUID()}
+			writePath := dir + "/" + randUUID()
+
+			var buf bytes.Buffer
+			readSize, readErr := store.Read(path, &buf, pair)
+
+			_, writeErr := store.Write(writePath, bytes.NewReader(content), size, pair)
+			if writeErr == nil {
+				defer func() {
+					err := store.Delete(writePath)
+					if err != nil {
+						t.Error(err)
+					}
+				}()
+			}
+
+			o, statErr := store.Stat(path, pair)
+
+			var listed []string
+			it, listErr := store.List(dir+"/", pairs.WithListMode(types.ListModeDir), pair)
+			for listErr == nil {
+				lo, err := it.Next()
+				if err == types.IterateDone {
+					break
+				}
+				if err != nil {
+					listErr = err
+					break
+				}
+				listed = append(listed, lo.Path)
+			}
+
+			// Delete is called last as it will remove the file if the pair is ignored.
+			deleteErr := store.Delete(path, pair)
+
+			errs := []struct {
+				op   string
+				path string
+				err  error
+			}{
+				{"read", path, readErr},
+				{"write", writePath, writeErr},
+				{"stat", path, statErr},
+				{"list", dir + "/", listErr},
+				{"delete", path, deleteErr},
+			}
+
+			rejected := 0
+			for _, v := range errs {
+				if v.err != nil {
+					rejected++
+				}
+			}
+
+			Convey("The pair should be either ignored or rejected by all operations", func() {
+				So(rejected, ShouldBeIn, []int{0, len(errs)})
+			})
+
+			if rejected == 0 {
+				t.Logf("%s ignores unsupported pairs", store)
+
+				Convey("The operations should work as if the pair is not passed", func() {
+					So(readSize, ShouldEqual, size)
+					So(sha256.Sum256(buf.Bytes()), ShouldResemble, sha256.Sum256(content))
+
+					So(o.Path, ShouldEqual, path)
+					So(listed, ShouldContain, path)
+					So(listed, ShouldContain, writePath)
+
+					_, err := store.Stat(path)
+					So(errors.Is(err, services.ErrObjectNotExist), ShouldBeTrue)
+				})
+				return
+			}
+
+			Convey("The errors should be PairUnsupportedError with op and path", func() {
+				for _, v := range errs {
+					So(errors.Is(v.err, services.ErrCapabilityInsufficient), ShouldBeTrue)
+
+					var pe services.PairUnsupportedError
+					So(errors.As(v.err, &pe), ShouldBeTrue)
+					So(pe.Pair.Key, ShouldEqual, unsupportedPairKey)
+
+					So(v.err, shouldBeStorageError, v.op, v.path)
+				}
+			})
+
+			Convey("The rejected operations should have no side effects", func() {
+				_, err := store.Stat(path)
+				So(err, ShouldBeNil)
+
+				_, err = store.Stat(writePath)
+				So(errors.Is(err, services.ErrObjectNotExist), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+// TestDefaultPairs creates a store with default pairs by newStore, and checks that the
+// default pairs are applied to every operation unless overwritten by passed pairs.
+//
+// The suite will be skipped if newStore returns ErrCapabilityInsufficient, as default
+// pairs are optional for services.
+func TestDefaultPairs(t *testing.T, newStore func(pairs ...types.Pair) (types.Storager, error)) {
+	setupRand(t)
+
+	var lock sync.Mutex
+	var callbackSize int64
+	callbackSizeAndReset := func() int64 {
+		lock.Lock()
+		defer lock.Unlock()
+
+		n := callbackSize
+		callbackSize = 0
+		return n
+	}
+
+	contentType := "application/x-" + randUUID()
+	store, err := newStore(
+		pairs.WithDefaultContentType(contentType),
+		pairs.WithDefaultIoCallback(func(bs []byte) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			callbackSize += int64(len(bs))
+		}),
+	)
+	if errors.Is(err, services.ErrCapabilityInsufficient) {
+		t.Skipf("default pairs are not supported: %v", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("Given a Storager with default pairs", t, func() {
+		So(store, ShouldNotBeNil)
+
+		Convey("When Write a file without pairs", func() {
+			path := randUUID()
+			size := randInt63n(4*1024*1024) + 1 // Max file size is 4MB
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+
+			callbackSizeAndReset()
+			_, err := store.Write(path, bytes.NewReader(content), size)
+			if err != nil {
+				t.Fatal(err)
+			}
+			writeCallbackSize := callbackSizeAndReset()
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			Convey("The default io callback should be called", func() {
+				So(writeCallbackSize, ShouldEqual, size)
+			})
+
+			Convey("The default content type should be applied", func() {
+				o, err := store.Stat(path)
+				So(err, ShouldBeNil)
+
+				ct, ok := o.GetContentType()
+				So(ok, ShouldBeTrue)
+				So(ct, ShouldEqual, contentType)
+			})
+
+			Convey("When Read the file without pairs", func() {
+				var buf bytes.Buffer
+				n, err := store.Read(path, &buf)
+				readCallbackSize := callbackSizeAndReset()
+
+				Convey("The default io callback should be called", func() {
+					So(err, ShouldBeNil)
+					So(n, ShouldEqual, size)
+					So(readCallbackSize, ShouldEqual, size)
+				})
+			})
+		})
+
+		Convey("When Write a file with pairs", func() {
+			path := randUUID()
+			size := randInt63n(4*1024*1024) + 1 // Max file size is 4MB
+			ct := "application/x-" + randUUID()
+
+			var passedCallbackSize int64
+			callbackSizeAndReset()
+			_, err := store.Write(path, io.LimitReader(randReader(), size), size,
+				pairs.WithContentType(ct),
+				pairs.WithIoCallback(func(bs []byte) {
+					passedCallbackSize += int64(len(bs))
+				}),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defaultCallbackSize := callbackSizeAndReset()
+
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			Convey("The passed io callback should be called instead", func() {
+				So(passedCallbackSize, ShouldEqual, size)
+				So(defaultCallbackSize, ShouldEqual, 0)
+			})
+
+			Convey("The passed content type should be applied instead", func() {
+				o, err := store.Stat(path)
+				So(err, ShouldBeNil)
+
+				v, ok := o.GetContentType()
+				So(ok, ShouldBeTrue)
+				So(v, ShouldEqual, ct)
+			})
+		})
+	})
+}
@@ -0,0 +1,223 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	pathpkg "path"
+	"sort"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// maxKeyLength is the max length of keys in most object storage services.
+const maxKeyLength = 1024
+
+// listFiles lists all files under dir recursively, and returns their paths without
+// trailing slash. Dirs will be listed one by one with ListModeDir if ListModePrefix
+// is not supported.
+func listFiles(store types.Storager, dir string) ([]string, error) {
+	entries, _, err := listAll(store, dir, types.ListModePrefix)
+	if errors.Is(err, services.ErrListModeInvalid) {
+		entries, _, err = listAll(store, dir, types.ListModeDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for path, e := range entries {
+		if !e.dir {
+			files = append(files, path)
+			continue
+		}
+		sub, err := listFiles(store, path+"/")
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, sub...)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// pathCase is a path used by TestPathEdgeCases.
+type pathCase struct {
+	name string
+	// path is the path passed to operations.
+	path string
+	// listed is the path expected to be returned by List.
+	listed string
+	// clean means services could return the cleaned path while listing.
+	clean bool
+}
+
+// longPath returns a path under base whose key is exactly maxKeyLength bytes
+// including the work dir, it's split into segments as most file systems limit
+// the length of a single name. ok will be false if the work dir is too long to
+// leave room for a segment under base.
+func longPath(workDir, base string) (path string, ok bool) {
+	n := maxKeyLength - len(base)
+	if prefix := strings.Trim(workDir, "/"); prefix != "" {
+		n -= len(prefix) + 1
+	}
+	// Every segment takes a slash and at least 1 byte.
+	if n < 2 {
+		return "", false
+	}
+
+	var b strings.Builder
+	b.WriteString(base)
+	for n > 0 {
+		seg := 128
+		if seg > n-1 {
+			seg = n - 1
+		}
+		// Leave at least 1 byte for the last segment, otherwise the path will
+		// end with a slash.
+		if n-seg-1 == 1 {
+			seg--
+		}
+		b.WriteString("/")
+		b.WriteString(strings.Repeat("k", seg))
+		n -= seg + 1
+	}
+	return b.String(), true
+}
+
+// TestPathEdgeCases writes, stats, lists and deletes objects with unusual names, and
+// checks that the names are returned as is.
+//
+// Paths with a trailing slash refer to dirs in go-storage, so Write to them should
+// either fail with ErrObjectModeInvalid, or create an object which is listed as a dir
+// like object storage services.
+func TestPathEdgeCases(t *testing.T, store types.Storager) {
+	setupRand(t)
+
+	Convey("Given a basic Storager", t, func() {
+		So(store, ShouldNotBeNil)
+
+		workDir := store.Metadata().WorkDir
+		base := randUUID()
+
+		cases := []pathCase{
+			{"unicode", base + "/文件-ファイル-파일-файл-😀", base + "/文件-ファイル-파일-файл-😀", false},
+			{"NFC unicode", base + "/caf\u00e9", base + "/caf\u00e9", false},
+			{"NFD unicode", base + "/cafe\u0301", base + "/cafe\u0301", false},
+			{"spaces", base + "/a file with  spaces", base + "/a file with  spaces", false},
+			{"percent", base + "/100% done %2F%zz", base + "/100% done %2F%zz", false},
+			{"plus", base + "/a+b+c", base + "/a+b+c", false},
+			{"question mark", base + "/why?x=1&y=2", base + "/why?x=1&y=2", false},
+			{"hash", base + "/issue#1", base + "/issue#1", false},
+			{"leading slash", pathpkg.Join(workDir, base, "absolute"), base + "/absolute", false},
+			{"dot dot segment", base + "/a/../b", base + "/a/../b", true},
+		}
+		if path, ok := longPath(workDir, base); ok {
+			cases = append(cases, pathCase{"1024 bytes key", path, path, false})
+		} else {
+			t.Logf("work dir %q is too long to write a %d bytes key", workDir, maxKeyLength)
+		}
+
+		for _, c := range cases {
+			c := c
+
+			Convey(fmt.Sprintf("When Write a file with %s path", c.name), func() {
+				size := randInt63n(1024) + 1
+				content, _ := ioutil.ReadAll(io.LimitReader(randReader(), size))
+
+				_, err := store.Write(c.path, bytes.NewReader(content), size)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				defer func() {
+					err := store.Delete(c.path)
+					if err != nil {
+						t.Error(err)
+					}
+				}()
+
+				Convey("Stat should return the same path", func() {
+					o, err := store.Stat(c.path)
+					So(err, ShouldBeNil)
+					So(o.Path, ShouldEqual, c.path)
+
+					osize, ok := o.GetContentLength()
+					So(ok, ShouldBeTrue)
+					So(osize, ShouldEqual, size)
+				})
+
+				Convey("Read should return the same content", func() {
+					var buf bytes.Buffer
+					n, err := store.Read(c.path, &buf)
+					So(err, ShouldBeNil)
+					So(n, ShouldEqual, size)
+					So(sha256.Sum256(buf.Bytes()), ShouldResemble, sha256.Sum256(content))
+				})
+
+				Convey("List should return the same path", func() {
+					files, err := listFiles(store, base+"/")
+					So(err, ShouldBeNil)
+
+					listed := c.listed
+					if c.clean && (len(files) != 1 || files[0] != listed) {
+						listed = pathpkg.Clean(listed)
+					}
+					So(files, ShouldResemble, []string{listed})
+				})
+
+				Convey("When Delete the file", func() {
+					err := store.Delete(c.path)
+
+					Convey("The file should not exist", func() {
+						So(err, ShouldBeNil)
+
+						_, err := store.Stat(c.path)
+						So(errors.Is(err, services.ErrObjectNotExist), ShouldBeTrue)
+
+						files, err := listFiles(store, base+"/")
+						So(err, ShouldBeNil)
+						So(files, ShouldBeEmpty)
+					})
+				})
+			})
+		}
+
+		Convey("When Write a file with trailing slash path", func() {
+			path := base + "/trailing-slash/"
+			size := randInt63n(1024) + 1
+
+			_, err := store.Write(path, io.LimitReader(randReader(), size), size)
+			if err == nil {
+				defer func() {
+					err := store.Delete(path)
+					if err != nil {
+						t.Error(err)
+					}
+				}()
+			}
+
+			Convey("The path should be either rejected or listed as a dir", func() {
+				entries, _, lerr := listAll(store, base+"/", types.ListModeDir)
+				So(lerr, ShouldBeNil)
+
+				if err != nil {
+					So(errors.Is(err, services.ErrObjectModeInvalid), ShouldBeTrue)
+					So(entries, ShouldBeEmpty)
+					return
+				}
+				So(entries, ShouldResemble, map[string]listEntry{
+					base + "/trailing-slash": {dir: true},
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,52 @@
+package tests
+
+// Profile describes the behaviors which vary between services, suites will
+// select the expectations and edge cases to test from it.
+//
+// The zero value is a conservative profile which is expected to be satisfied by most
+// services, fields only need to be set where the service differs from it.
+type Profile struct {
+	// VirtualDir means the service's dir is a virtual dir which is only a prefix of objects,
+	// copy and move to an existing dir will overwrite it instead of returning ErrObjectModeInvalid.
+	VirtualDir bool
+	// NoEmptyObjects means the service can't write an object with 0 size.
+	NoEmptyObjects bool
+	// MaxPartNumber is the upper bound (exclusive) of the part number used in multipart tests,
+	// defaultMaxPartNumber will be used if 0.
+	MaxPartNumber int
+	// DiscontinuousPartNumbers means the part numbers don't need to be continuous or start
+	// from 0 while completing a multipart upload, like `s3`. Otherwise they must be, like `cos`.
+	DiscontinuousPartNumbers bool
+	// MinPartSize is the min size of every part except the last one while completing
+	// a multipart upload, defaultMinPartSize will be used if 0. Set it to 1 for services
+	// without a limit.
+	MinPartSize int64
+	// UserMetadataPairKey is the key of the service specific pair which writes user metadata
+	// with a map[string]string value, user metadata tests will be skipped if empty.
+	UserMetadataPairKey string
+	// PermissionDeniedPath is an existing path which the credential is not allowed to read,
+	// permission denied tests will be skipped if empty.
+	PermissionDeniedPath string
+}
+
+const (
+	// defaultMaxPartNumber is lower than the limits of most services.
+	defaultMaxPartNumber = 1000
+	// defaultMinPartSize is the min part size of `s3`, which is the largest one in
+	// most services.
+	defaultMinPartSize = 5 * 1024 * 1024
+)
+
+func (p Profile) maxPartNumber() int {
+	if p.MaxPartNumber == 0 {
+		return defaultMaxPartNumber
+	}
+	return p.MaxPartNumber
+}
+
+func (p Profile) minPartSize() int64 {
+	if p.MinPartSize == 0 {
+		return defaultMinPartSize
+	}
+	return p.MinPartSize
+}
@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"io"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/beyondstorage/go-storage/v4/pkg/randbytes"
+)
+
+// SeedEnv is the environment variable used to override the random seed, so that
+// a failed run could be reproduced with the same sizes, offsets and content.
+const SeedEnv = "STORAGE_INTEGRATION_TEST_SEED"
+
+var (
+	randLock sync.Mutex
+	// randSource is shared by all suites, it MUST be accessed with randLock held.
+	randSource = rand.New(rand.NewSource(time.Now().UnixNano()))
+	// fixedSeed is the seed set by Options.Seed, 0 means not set.
+	fixedSeed int64
+)
+
+// setupRand will reset the random source with a seed and log it.
+//
+// The seed is picked from SeedEnv, Options.Seed and current time in order.
+func setupRand(t *testing.T) {
+	seed := time.Now().UnixNano()
+
+	randLock.Lock()
+	if fixedSeed != 0 {
+		seed = fixedSeed
+	}
+	randLock.Unlock()
+
+	if v := os.Getenv(SeedEnv); v != "" {
+		s, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			t.Fatalf("invalid %s %q: %v", SeedEnv, v, err)
+		}
+		seed = s
+	}
+
+	t.Logf("random seed: %d, set %s=%d to reproduce", seed, SeedEnv, seed)
+
+	randLock.Lock()
+	randSource.Seed(seed)
+	randLock.Unlock()
+}
+
+// setFixedSeed will make all following suites use seed, and returns a func to restore.
+func setFixedSeed(seed int64) func() {
+	randLock.Lock()
+	defer randLock.Unlock()
+
+	prev := fixedSeed
+	fixedSeed = seed
+	return func() {
+		randLock.Lock()
+		defer randLock.Unlock()
+
+		fixedSeed = prev
+	}
+}
+
+func randInt63() int64 {
+	randLock.Lock()
+	defer randLock.Unlock()
+
+	return randSource.Int63()
+}
+
+func randInt63n(n int64) int64 {
+	randLock.Lock()
+	defer randLock.Unlock()
+
+	return randSource.Int63n(n)
+}
+
+func randIntn(n int) int {
+	randLock.Lock()
+	defer randLock.Unlock()
+
+	return randSource.Intn(n)
+}
+
+func randPerm(n int) []int {
+	randLock.Lock()
+	defer randLock.Unlock()
+
+	return randSource.Perm(n)
+}
+
+// randReader returns a stream of random bytes derived from the shared random source.
+func randReader() io.Reader {
+	return seededReader(randInt63())
+}
+
+// seededReader returns a stream of random bytes which is the same for the same seed,
+// so that content could be generated again for verifying without being held in memory.
+func seededReader(seed int64) io.Reader {
+	return &randbytes.Rand{Source: rand.NewSource(seed)}
+}
+
+// randUUID returns a new random uuid which is NOT derived from the seed, as it's
+// used for paths, and runs with the same seed must not reuse the paths left behind
+// by each other.
+func randUUID() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+func TestReacher(t *testing.T, store types.Storager) {
+	setupRand(t)
+
+	Convey("Given a basic Storager", t, func() {
+		r, ok := store.(types.Reacher)
+		So(ok, ShouldBeTrue)
+
+		Convey("When Read via Reach", func() {
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			content, err := ioutil.ReadAll(io.LimitReader(randReader(), size))
+			if err != nil {
+				t.Error(err)
+			}
+
+			path := randUUID()
+			_, err = store.Write(path, bytes.NewReader(content), size)
+			if err != nil {
+				t.Error(err)
+			}
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			url, err := r.Reach(path, pairs.WithExpire(time.Hour))
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+				So(url, ShouldNotBeEmpty)
+			})
+
+			client := http.Client{}
+			resp, err := client.Get(url)
+			Convey("The request returned error should be nil", func() {
+				So(err, ShouldBeNil)
+				So(resp, ShouldNotBeNil)
+				So(resp.StatusCode, ShouldEqual, http.StatusOK)
+			})
+
+			defer resp.Body.Close()
+
+			buf, err := ioutil.ReadAll(resp.Body)
+			Convey("The content should be match", func() {
+				So(err, ShouldBeNil)
+				So(buf, ShouldNotBeNil)
+
+				So(resp.ContentLength, ShouldEqual, size)
+				So(sha256.Sum256(buf), ShouldResemble, sha256.Sum256(content))
+			})
+		})
+
+		Convey("When Reach a not existing file", func() {
+			path := randUUID()
+
+			url, err := r.Reach(path, pairs.WithExpire(time.Hour))
+
+			Convey("The url should not be reachable", func() {
+				// Service could either refuse to generate the url, or generate
+				// an url which returns a client error. Services like s3 and oss
+				// return 403 instead of 404 for missing keys without list permission.
+				if err != nil {
+					return
+				}
+
+				client := http.Client{}
+				resp, err := client.Get(url)
+				So(err, ShouldBeNil)
+				defer resp.Body.Close()
+
+				So(resp.StatusCode, ShouldBeBetweenOrEqual, http.StatusBadRequest, 499)
+			})
+		})
+
+		Convey("When Reach with a very short expire", func() {
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			path := randUUID()
+			_, err := store.Write(path, io.LimitReader(randReader(), size), size)
+			if err != nil {
+				t.Error(err)
+			}
+			defer func() {
+				err := store.Delete(path)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			url, err := r.Reach(path, pairs.WithExpire(time.Second))
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+				So(url, ShouldNotBeEmpty)
+			})
+
+			// Wait for the url expired.
+			time.Sleep(3 * time.Second)
+
+			Convey("The url should not be reachable after expired", func() {
+				client := http.Client{}
+				resp, err := client.Get(url)
+				So(err, ShouldBeNil)
+				defer resp.Body.Close()
+
+				So(resp.StatusCode, ShouldNotEqual, http.StatusOK)
+			})
+		})
+	})
+}
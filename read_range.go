@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	ps "github.com/beyondstorage/go-storage/v4/pairs"
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+// readRangeSize is the size of the object used in TestReadRange.
+const readRangeSize = 1024
+
+func TestReadRange(t *testing.T, store types.Storager) {
+	TestReadRangeWithProfile(t, store, Profile{})
+}
+
+// TestReadRangeWithProfile reads at the boundaries of objects with offset and size,
+// the expected semantics are:
+//
+//   - Reading with offset equal to the object size returns 0 bytes without error.
+//   - Reading with offset larger than the object size either returns 0 bytes without
+//     error like fs, or returns an error which wraps ErrRestrictionDissatisfied and
+//     writes nothing. Services are not consistent here, so both are accepted.
+//   - Reading with size larger than the remaining bytes returns the remaining bytes
+//     without error.
+//   - Reading with size 0 returns 0 bytes without error.
+func TestReadRangeWithProfile(t *testing.T, store types.Storager, p Profile) {
+	setupRand(t)
+
+	cases := []struct {
+		name       string
+		objectSize int64
+		pairs      []types.Pair
+		// offset and length is the range of the content expected to be returned.
+		offset int64
+		length int64
+		// outOfRange means ErrRestrictionDissatisfied is also accepted.
+		outOfRange bool
+	}{
+		{"offset equal to size", readRangeSize, []types.Pair{ps.WithOffset(readRangeSize)}, readRangeSize, 0, false},
+		{"offset larger than size", readRangeSize, []types.Pair{ps.WithOffset(readRangeSize + 1)}, readRangeSize, 0, true},
+		{"size larger than remaining", readRangeSize, []types.Pair{ps.WithOffset(readRangeSize - 24), ps.WithSize(100)}, readRangeSize - 24, 24, false},
+		{"size larger than object size", readRangeSize, []types.Pair{ps.WithSize(readRangeSize * 2)}, 0, readRangeSize, false},
+		{"zero size", readRangeSize, []types.Pair{ps.WithSize(0)}, 0, 0, false},
+		{"zero size at offset", readRangeSize, []types.Pair{ps.WithOffset(10), ps.WithSize(0)}, 10, 0, false},
+		{"zero size at the end", readRangeSize, []types.Pair{ps.WithOffset(readRangeSize), ps.WithSize(0)}, readRangeSize, 0, false},
+		{"offset 0 on an empty object", 0, []types.Pair{ps.WithOffset(0)}, 0, 0, false},
+		{"offset on an empty object", 0, []types.Pair{ps.WithOffset(1)}, 0, 0, true},
+		{"size on an empty object", 0, []types.Pair{ps.WithSize(1)}, 0, 0, false},
+	}
+
+	Convey("Given a basic Storager", t, func() {
+		So(store, ShouldNotBeNil)
+
+		for _, c := range cases {
+			c := c
+			if c.objectSize == 0 && p.NoEmptyObjects {
+				continue
+			}
+
+			Convey("When Read with "+c.name, func() {
+				content, _ := ioutil.ReadAll(io.LimitReader(randReader(), c.objectSize))
+
+				path := randUUID()
+				_, err := store.Write(path, bytes.NewReader(content), c.objectSize)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				defer func() {
+					err := store.Delete(path)
+					if err != nil {
+						t.Error(err)
+					}
+				}()
+
+				var buf bytes.Buffer
+				n, err := store.Read(path, &buf, c.pairs...)
+
+				if c.outOfRange && err != nil {
+					t.Logf("%s rejects reading with %s", store, c.name)
+
+					Convey("The error should be ErrRestrictionDissatisfied", func() {
+						So(errors.Is(err, services.ErrRestrictionDissatisfied), ShouldBeTrue)
+						So(err, shouldBeStorageError, "read", path)
+					})
+
+					Convey("Nothing should be written", func() {
+						So(n, ShouldEqual, 0)
+						So(buf.Len(), ShouldEqual, 0)
+					})
+					return
+				}
+
+				Convey("The error should be nil", func() {
+					So(err, ShouldBeNil)
+				})
+
+				Convey("The content should be match", func() {
+					So(n, ShouldEqual, c.length)
+					So(bytes.Equal(buf.Bytes(), content[c.offset:c.offset+c.length]), ShouldBeTrue)
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Name    string           `xml:"name,attr"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Content string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report as JUnit XML, every suite is a testsuite and every
+// scenario is a testcase.
+func (r *Report) WriteJUnit(w io.Writer) error {
+	doc := junitTestSuites{Name: r.Service}
+
+	for _, s := range r.Suites {
+		ts := junitTestSuite{Name: r.Service + "/" + s.Name}
+		className := r.Service + "." + s.Name
+
+		for _, sc := range s.Scenarios {
+			tc := junitTestCase{Name: sc.Name, ClassName: className}
+			switch sc.Status {
+			case StatusFail:
+				var failures []string
+				for _, a := range sc.Assertions {
+					if a.Status != StatusFail {
+						continue
+					}
+					if a.File == "" {
+						failures = append(failures, a.Failure)
+						continue
+					}
+					failures = append(failures, fmt.Sprintf("%s:%d\n%s", a.File, a.Line, a.Failure))
+				}
+				tc.Failure = &junitMessage{
+					Message: fmt.Sprintf("%d assertions failed", len(failures)),
+					Content: strings.Join(failures, "\n\n"),
+				}
+			case StatusSkip:
+				tc.Skipped = &junitMessage{}
+			}
+			ts.Cases = append(ts.Cases, tc)
+		}
+
+		// Suites without any scenario are skipped or failed before Convey,
+		// record the suite itself as a testcase to keep the reason.
+		if len(ts.Cases) == 0 {
+			tc := junitTestCase{Name: s.Name, ClassName: className}
+			switch s.Status {
+			case StatusFail:
+				tc.Failure = &junitMessage{Message: "suite failed", Content: s.Output}
+			case StatusSkip:
+				tc.Skipped = &junitMessage{Message: strings.TrimSpace(s.Output)}
+			}
+			ts.Cases = append(ts.Cases, tc)
+		}
+
+		for _, tc := range ts.Cases {
+			ts.Tests++
+			if tc.Failure != nil {
+				ts.Failures++
+			}
+			if tc.Skipped != nil {
+				ts.Skipped++
+			}
+		}
+		doc.Suites = append(doc.Suites, ts)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,65 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteMatrix writes a markdown compatibility matrix of reports, every row is a suite
+// and every column is a service.
+//
+// Cells are the status of the suite with the passed and total assertions, and "-" for
+// suites which are not run by the service.
+func WriteMatrix(w io.Writer, reports ...*Report) error {
+	var names []string
+	interfaces := make(map[string][]string)
+	results := make(map[string]map[string]*Suite)
+
+	for _, r := range reports {
+		results[r.Service] = make(map[string]*Suite)
+		for _, s := range r.Suites {
+			if _, ok := interfaces[s.Name]; !ok {
+				names = append(names, s.Name)
+				interfaces[s.Name] = s.Interfaces
+			}
+			results[r.Service][s.Name] = s
+		}
+	}
+
+	header := []string{"Suite", "Interfaces"}
+	for _, r := range reports {
+		header = append(header, r.Service)
+	}
+	rows := [][]string{header, make([]string, len(header))}
+	for i := range header {
+		rows[1][i] = "---"
+	}
+
+	for _, name := range names {
+		row := []string{name, strings.Join(interfaces[name], ", ")}
+		for _, r := range reports {
+			row = append(row, matrixCell(results[r.Service][name]))
+		}
+		rows = append(rows, row)
+	}
+
+	for _, row := range rows {
+		if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(row, " | ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func matrixCell(s *Suite) string {
+	if s == nil {
+		return "-"
+	}
+	if s.Status == StatusSkip {
+		return string(StatusSkip)
+	}
+
+	pass, fail, _ := s.Count()
+	return fmt.Sprintf("%s (%d/%d)", s.Status, pass, pass+fail)
+}
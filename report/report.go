@@ -0,0 +1,285 @@
+// Package report builds machine-readable conformance reports from the output of
+// `go test -v` with `GOCONVEY_REPORTER=json`, which could be written as JSON, JUnit XML
+// and a service-by-suite compatibility matrix.
+//
+// The output is expected to be produced by RunAll, so that every suite is a subtest:
+//
+//	GOCONVEY_REPORTER=json go test -v -run TestIntegration ./... > memory.log
+package report
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/smartystreets/goconvey/convey/reporting"
+
+	"github.com/beyondstorage/go-integration-test/v4/suite"
+)
+
+// Status is the status of a suite or a scenario.
+type Status string
+
+// All available statuses.
+const (
+	StatusPass Status = "pass"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip"
+)
+
+// Report is the conformance report of a service.
+type Report struct {
+	Service string   `json:"service"`
+	Suites  []*Suite `json:"suites"`
+}
+
+// Suite is the result of a suite.
+type Suite struct {
+	Name string `json:"name"`
+	// Test is the full name of the go test which runs this suite.
+	Test       string   `json:"test"`
+	Interfaces []string `json:"interfaces"`
+	Status     Status   `json:"status"`
+	// Output is the log output of the suite, including the skip or failure message.
+	Output    string      `json:"output,omitempty"`
+	Scenarios []*Scenario `json:"scenarios"`
+}
+
+// Scenario is the result of all assertions in a Convey scope.
+type Scenario struct {
+	// Name is the titles from the root Convey to this scope, joined by " / ".
+	Name       string       `json:"name"`
+	Status     Status       `json:"status"`
+	Assertions []*Assertion `json:"assertions"`
+}
+
+// Assertion is the result of a single So, goconvey only reports the file and line
+// of failed assertions in _test.go files, so they are empty for assertions in suites.
+type Assertion struct {
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Status  Status `json:"status"`
+	Failure string `json:"failure,omitempty"`
+}
+
+// Count returns the number of assertions in each status.
+func (s *Suite) Count() (pass, fail, skip int) {
+	for _, sc := range s.Scenarios {
+		for _, a := range sc.Assertions {
+			switch a.Status {
+			case StatusPass:
+				pass++
+			case StatusFail:
+				fail++
+			case StatusSkip:
+				skip++
+			}
+		}
+	}
+	return
+}
+
+var (
+	runLine    = regexp.MustCompile(`^=== RUN\s+(\S+)`)
+	resultLine = regexp.MustCompile(`^\s*--- (PASS|FAIL|SKIP): (\S+)`)
+)
+
+// Parse parses the output of `go test -v` with `GOCONVEY_REPORTER=json` into a Report.
+func Parse(service string, r io.Reader) (*Report, error) {
+	p := &parser{
+		report: &Report{Service: service},
+		suites: make(map[string]*Suite),
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+
+	var block []string
+	inBlock := false
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, reporting.OpenJson):
+			inBlock = true
+			block = block[:0]
+		case strings.HasPrefix(line, reporting.CloseJson):
+			inBlock = false
+			if err := p.story(block); err != nil {
+				return nil, err
+			}
+		case inBlock:
+			block = append(block, line)
+		case runLine.MatchString(line):
+			p.current = runLine.FindStringSubmatch(line)[1]
+		case resultLine.MatchString(line):
+			m := resultLine.FindStringSubmatch(line)
+			p.result(m[2], m[1])
+		case p.current != "" && strings.HasPrefix(line, "    "):
+			// go test -v streams logs of the running test with indent.
+			s := p.suite(p.current)
+			s.Output += strings.TrimSpace(line) + "\n"
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if inBlock {
+		return nil, fmt.Errorf("report: unclosed goconvey json block for %s", p.current)
+	}
+
+	// Remove the parent tests which run suites as subtests.
+	suites := p.report.Suites[:0]
+	for _, s := range p.report.Suites {
+		if p.hasSubtests(s.Test) {
+			continue
+		}
+		if s.Status == "" {
+			s.Status = scenariosStatus(s.Scenarios)
+		}
+		suites = append(suites, s)
+	}
+	p.report.Suites = suites
+	return p.report, nil
+}
+
+type parser struct {
+	report *Report
+	suites map[string]*Suite
+
+	// current is the name of the running test.
+	current string
+}
+
+// suite returns the suite run by test, and creates it if not exist.
+func (p *parser) suite(test string) *Suite {
+	// TestXxx/Appender/sub -> Appender, TestAppender -> Appender
+	parts := strings.Split(test, "/")
+	name := strings.TrimPrefix(parts[0], "Test")
+	if len(parts) > 1 {
+		test = parts[0] + "/" + parts[1]
+		name = parts[1]
+	}
+
+	if s, ok := p.suites[test]; ok {
+		return s
+	}
+	s := &Suite{
+		Name:       name,
+		Test:       test,
+		Interfaces: suite.Interfaces(name),
+	}
+	p.suites[test] = s
+	p.report.Suites = append(p.report.Suites, s)
+	return s
+}
+
+// result records the result of test, results of nested subtests in suites are ignored.
+func (p *parser) result(test, result string) {
+	if strings.Count(test, "/") > 1 {
+		return
+	}
+
+	s := p.suite(test)
+	switch result {
+	case "PASS":
+		s.Status = StatusPass
+	case "FAIL":
+		s.Status = StatusFail
+	case "SKIP":
+		s.Status = StatusSkip
+	}
+}
+
+// hasSubtests checks whether test runs other suites as subtests.
+func (p *parser) hasSubtests(test string) bool {
+	for name := range p.suites {
+		if strings.HasPrefix(name, test+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// story parses a goconvey json block, which contains all scopes of a root Convey.
+func (p *parser) story(block []string) error {
+	content := strings.TrimSuffix(strings.TrimSpace(strings.Join(block, "\n")), ",")
+
+	var scopes []reporting.ScopeResult
+	if err := json.Unmarshal([]byte("["+content+"]"), &scopes); err != nil {
+		return fmt.Errorf("report: parse goconvey json for %s: %w", p.current, err)
+	}
+
+	s := p.suite(p.current)
+
+	var titles []string
+	for _, scope := range scopes {
+		if scope.Depth < 1 || scope.Depth > len(titles)+1 {
+			return fmt.Errorf("report: invalid scope depth %d for %s", scope.Depth, p.current)
+		}
+		titles = append(titles[:scope.Depth-1], scope.Title)
+
+		if len(scope.Assertions) == 0 {
+			continue
+		}
+
+		sc := &Scenario{Name: strings.Join(titles, " / ")}
+		for _, v := range scope.Assertions {
+			a := &Assertion{Status: StatusPass}
+			// goconvey reports unknown callers with line -1.
+			if v.Line > 0 {
+				a.File, a.Line = v.File, v.Line
+			}
+			switch {
+			case v.Skipped:
+				a.Status = StatusSkip
+			case v.Failure != "":
+				a.Status = StatusFail
+				a.Failure = v.Failure
+			case v.Error != nil:
+				a.Status = StatusFail
+				a.Failure = fmt.Sprint(v.Error)
+			}
+			sc.Assertions = append(sc.Assertions, a)
+		}
+		sc.Status = assertionsStatus(sc.Assertions)
+		s.Scenarios = append(s.Scenarios, sc)
+	}
+	return nil
+}
+
+func assertionsStatus(as []*Assertion) Status {
+	status := StatusSkip
+	for _, a := range as {
+		switch a.Status {
+		case StatusFail:
+			return StatusFail
+		case StatusPass:
+			status = StatusPass
+		}
+	}
+	return status
+}
+
+func scenariosStatus(scs []*Scenario) Status {
+	status := StatusSkip
+	for _, sc := range scs {
+		switch sc.Status {
+		case StatusFail:
+			return StatusFail
+		case StatusPass:
+			status = StatusPass
+		}
+	}
+	return status
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(r)
+}
@@ -0,0 +1,117 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testdata/memory.log is recorded from TestIntegration in memory by:
+//
+//	GOCONVEY_REPORTER=json go test -v -run 'TestIntegration/(Linker|Blocker|Errors)$' ./memory/
+//
+// with PermissionDeniedPath set to a missing path, so that Errors has failed assertions.
+func parseLog(t *testing.T) *Report {
+	f, err := os.Open("testdata/memory.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	r, err := Parse("memory", f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestParse(t *testing.T) {
+	r := parseLog(t)
+
+	if r.Service != "memory" {
+		t.Errorf("expected service memory, got %s", r.Service)
+	}
+
+	expected := []struct {
+		name       string
+		test       string
+		interfaces []string
+		status     Status
+		pass, fail int
+	}{
+		{"Blocker", "TestIntegration/Blocker", []string{"Blocker"}, StatusSkip, 0, 0},
+		{"Errors", "TestIntegration/Errors", []string{"Storager"}, StatusFail, 26, 2},
+		{"Linker", "TestIntegration/Linker", []string{"Linker"}, StatusPass, 37, 0},
+	}
+	if len(r.Suites) != len(expected) {
+		t.Fatalf("expected %d suites, got %d", len(expected), len(r.Suites))
+	}
+	for i, e := range expected {
+		s := r.Suites[i]
+		if s.Name != e.name || s.Test != e.test || s.Status != e.status {
+			t.Errorf("suite %d: expected %s %s %s, got %s %s %s",
+				i, e.name, e.test, e.status, s.Name, s.Test, s.Status)
+		}
+		if !reflect.DeepEqual(s.Interfaces, e.interfaces) {
+			t.Errorf("suite %s: expected interfaces %v, got %v", s.Name, e.interfaces, s.Interfaces)
+		}
+		if pass, fail, _ := s.Count(); pass != e.pass || fail != e.fail {
+			t.Errorf("suite %s: expected %d passed and %d failed, got %d and %d",
+				s.Name, e.pass, e.fail, pass, fail)
+		}
+	}
+
+	if out := r.Suites[0].Output; !strings.Contains(out, "doesn't implement [types.Blocker]") {
+		t.Errorf("expected the skip message in output of Blocker, got %q", out)
+	}
+	if out := r.Suites[2].Output; !strings.Contains(out, "random seed") {
+		t.Errorf("expected the seed in output of Linker, got %q", out)
+	}
+
+	var failed []*Scenario
+	for _, sc := range r.Suites[1].Scenarios {
+		if sc.Status == StatusFail {
+			failed = append(failed, sc)
+		}
+	}
+	names := []string{
+		"Given a basic Storager / When Read a file without permission / The error should be ErrPermissionDenied",
+		"Given a basic Storager / When Stat a file without permission / The error should be ErrPermissionDenied",
+	}
+	if len(failed) != len(names) {
+		t.Fatalf("expected %d failed scenarios in Errors, got %d", len(names), len(failed))
+	}
+	for i, sc := range failed {
+		if sc.Name != names[i] {
+			t.Errorf("expected failed scenario %q, got %q", names[i], sc.Name)
+		}
+		// goconvey can't resolve the caller of assertions outside _test.go files.
+		a := sc.Assertions[0]
+		if a.File != "" || a.Line != 0 || !strings.Contains(a.Failure, "Expected: true") {
+			t.Errorf("expected the failure without file and line, got %+v", a)
+		}
+	}
+}
+
+func TestParseUnclosedBlock(t *testing.T) {
+	log := "=== RUN   TestIntegration/Linker\n>->->OPEN-JSON->->->\n{\n"
+	if _, err := Parse("memory", strings.NewReader(log)); err == nil {
+		t.Error("expected error for unclosed json block")
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	r := parseLog(t)
+
+	var buf bytes.Buffer
+	if err := r.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unknown file and line reported by goconvey are omitted.
+	if strings.Contains(buf.String(), `"file"`) || strings.Contains(buf.String(), "unknown file") {
+		t.Errorf("expected no assertion with file, got %s", buf.String())
+	}
+}
@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/beyondstorage/go-storage/v4/types"
+
+	"github.com/beyondstorage/go-integration-test/v4/suite"
+)
+
+// Options is the options used by RunAll.
+type Options struct {
+	// Profile is the service profile used by suites, the zero Profile will be used if nil.
+	Profile *Profile
+	// Seed is the random seed used by all suites, a random seed will be used if 0.
+	//
+	// SeedEnv takes precedence over Seed.
+	Seed int64
+	// LargeObjectSize is the size of the object used by TestLargeObject, the suite will be
+	// skipped if 0 as it's slow.
+	LargeObjectSize int64
+	// ListPagination enables TestListPagination, the suite will be skipped if false as
+	// it writes and deletes thousands of objects.
+	ListPagination bool
+	// NewStorager creates a store of the same service with extra pairs, it's used by
+	// TestDefaultPairs which will be skipped if nil.
+	NewStorager func(pairs ...types.Pair) (types.Storager, error)
+}
+
+func (opts Options) profile() Profile {
+	if opts.Profile == nil {
+		return Profile{}
+	}
+	return *opts.Profile
+}
+
+// suiteFuncs is the functions of suites in suite.Suites keyed by name.
+var suiteFuncs = map[string]func(t *testing.T, store types.Storager, opts Options){
+	"Appender":            func(t *testing.T, store types.Storager, _ Options) { TestAppender(t, store) },
+	"Blocker":             func(t *testing.T, store types.Storager, _ Options) { TestBlocker(t, store) },
+	"ContextCancellation": func(t *testing.T, store types.Storager, _ Options) { TestContextCancellation(t, store) },
+	"Copier": func(t *testing.T, store types.Storager, opts Options) {
+		TestCopierWithProfile(t, store, opts.profile())
+	},
+	"DefaultPairs": func(t *testing.T, _ types.Storager, opts Options) {
+		if opts.NewStorager == nil {
+			t.Skip("NewStorager is not set")
+		}
+		TestDefaultPairs(t, opts.NewStorager)
+	},
+	"Direr": func(t *testing.T, store types.Storager, _ Options) { TestDirer(t, store) },
+	"Errors": func(t *testing.T, store types.Storager, opts Options) {
+		TestErrorsWithProfile(t, store, opts.profile())
+	},
+	"FaultInjection": func(t *testing.T, store types.Storager, _ Options) { TestFaultInjection(t, store) },
+	"Fetcher":        func(t *testing.T, store types.Storager, _ Options) { TestFetcher(t, store) },
+	"LargeObject": func(t *testing.T, store types.Storager, opts Options) {
+		if opts.LargeObjectSize == 0 {
+			t.Skip("LargeObjectSize is not set")
+		}
+		TestLargeObject(t, store, opts.LargeObjectSize)
+	},
+	"Linker":        func(t *testing.T, store types.Storager, _ Options) { TestLinker(t, store) },
+	"ListHierarchy": func(t *testing.T, store types.Storager, _ Options) { TestListHierarchy(t, store) },
+	"ListPagination": func(t *testing.T, store types.Storager, opts Options) {
+		if !opts.ListPagination {
+			t.Skip("ListPagination is not set")
+		}
+		TestListPagination(t, store)
+	},
+	"Metadata": func(t *testing.T, store types.Storager, opts Options) {
+		TestMetadataWithProfile(t, store, opts.profile())
+	},
+	"Model": func(t *testing.T, store types.Storager, opts Options) {
+		TestModelWithProfile(t, store, opts.profile())
+	},
+	"Mover": func(t *testing.T, store types.Storager, opts Options) {
+		TestMoverWithProfile(t, store, opts.profile())
+	},
+	"Multiparter": func(t *testing.T, store types.Storager, opts Options) {
+		TestMultiparterWithProfile(t, store, opts.profile())
+	},
+	"Pager":         func(t *testing.T, store types.Storager, _ Options) { TestPager(t, store) },
+	"PathEdgeCases": func(t *testing.T, store types.Storager, _ Options) { TestPathEdgeCases(t, store) },
+	"Reacher":       func(t *testing.T, store types.Storager, _ Options) { TestReacher(t, store) },
+	"ReadRange": func(t *testing.T, store types.Storager, opts Options) {
+		TestReadRangeWithProfile(t, store, opts.profile())
+	},
+	"StoragerConcurrency":     func(t *testing.T, store types.Storager, _ Options) { TestStoragerConcurrency(t, store) },
+	"UnsupportedPairs":        func(t *testing.T, store types.Storager, _ Options) { TestUnsupportedPairs(t, store) },
+	"StorageHTTPSignerRead":   func(t *testing.T, store types.Storager, _ Options) { TestStorageHTTPSignerRead(t, store) },
+	"StorageHTTPSignerWrite":  func(t *testing.T, store types.Storager, _ Options) { TestStorageHTTPSignerWrite(t, store) },
+	"StorageHTTPSignerDelete": func(t *testing.T, store types.Storager, _ Options) { TestStorageHTTPSignerDelete(t, store) },
+	"MultipartHTTPSigner":     func(t *testing.T, store types.Storager, _ Options) { TestMultipartHTTPSigner(t, store) },
+}
+
+// RunAll will run TestStorager and all suites whose interfaces are implemented by store
+// as subtests. Suites that store doesn't support will be reported as skipped.
+func RunAll(t *testing.T, store types.Storager, opts Options) {
+	if opts.Seed != 0 {
+		defer setFixedSeed(opts.Seed)()
+	}
+
+	t.Run("Storager", func(t *testing.T) {
+		TestStoragerWithProfile(t, store, opts.profile())
+	})
+
+	for _, s := range suite.Suites {
+		s := s
+		t.Run(s.Name, func(t *testing.T) {
+			fn, ok := suiteFuncs[s.Name]
+			if !ok {
+				t.Fatalf("suite %s is not implemented", s.Name)
+			}
+			if m := missing(store, s.Requires); len(m) > 0 {
+				t.Skipf("%s doesn't implement %v", store, m)
+			}
+			fn(t, store, opts)
+		})
+	}
+}
+
+// missing returns the interfaces in names which are not implemented by store.
+func missing(store types.Storager, names []string) []string {
+	var m []string
+	for _, name := range names {
+		if !implements(store, name) {
+			m = append(m, "types."+name)
+		}
+	}
+	return m
+}
+
+// implements checks whether store implements the named interface in types.
+func implements(store types.Storager, name string) (ok bool) {
+	switch name {
+	case "Appender":
+		_, ok = store.(types.Appender)
+	case "Blocker":
+		_, ok = store.(types.Blocker)
+	case "Copier":
+		_, ok = store.(types.Copier)
+	case "Direr":
+		_, ok = store.(types.Direr)
+	case "Fetcher":
+		_, ok = store.(types.Fetcher)
+	case "Linker":
+		_, ok = store.(types.Linker)
+	case "Mover":
+		_, ok = store.(types.Mover)
+	case "Multiparter":
+		_, ok = store.(types.Multiparter)
+	case "Pager":
+		_, ok = store.(types.Pager)
+	case "Reacher":
+		_, ok = store.(types.Reacher)
+	case "StorageHTTPSigner":
+		_, ok = store.(types.StorageHTTPSigner)
+	case "MultipartHTTPSigner":
+		_, ok = store.(types.MultipartHTTPSigner)
+	default:
+		panic(fmt.Sprintf("unknown interface %s", name))
+	}
+	return
+}
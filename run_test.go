@@ -0,0 +1,36 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/beyondstorage/go-integration-test/v4/suite"
+)
+
+func TestSuiteFuncs(t *testing.T) {
+	names := make(map[string]bool)
+	for _, s := range suite.Suites {
+		if names[s.Name] {
+			t.Errorf("suite %s is duplicated", s.Name)
+		}
+		names[s.Name] = true
+
+		if _, ok := suiteFuncs[s.Name]; !ok {
+			t.Errorf("suite %s is not implemented", s.Name)
+		}
+		for _, name := range s.Requires {
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Errorf("suite %s requires unknown interface %s", s.Name, name)
+					}
+				}()
+				implements(nil, name)
+			}()
+		}
+	}
+	for name := range suiteFuncs {
+		if !names[name] {
+			t.Errorf("suite %s is not listed in suite.Suites", name)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/beyondstorage/go-storage/v4/services"
+	"github.com/beyondstorage/go-storage/v4/types"
+)
+
+func TestServicer(t *testing.T, srv types.Servicer) {
+	setupRand(t)
+
+	Convey("Given a basic Servicer", t, func() {
+		So(srv, ShouldNotBeNil)
+
+		Convey("When String called", func() {
+			s := srv.String()
+
+			Convey("The string should not be empty", func() {
+				So(s, ShouldNotBeEmpty)
+			})
+		})
+
+		Convey("When Create a storage", func() {
+			name := randUUID()
+			store, err := srv.Create(name)
+
+			defer func() {
+				err := srv.Delete(name)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+				So(store, ShouldNotBeNil)
+			})
+
+			Convey("The storage name should be match", func() {
+				So(store.Metadata().Name, ShouldEqual, name)
+			})
+		})
+
+		Convey("When Get a storage", func() {
+			name := randUUID()
+			_, err := srv.Create(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				err := srv.Delete(name)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			store, err := srv.Get(name)
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+				So(store, ShouldNotBeNil)
+			})
+
+			Convey("The storage name should be match", func() {
+				So(store.Metadata().Name, ShouldEqual, name)
+			})
+
+			Convey("The storage should be able to write, read and delete", func() {
+				size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+				content, err := ioutil.ReadAll(io.LimitReader(randReader(), size))
+				So(err, ShouldBeNil)
+
+				path := randUUID()
+				n, err := store.Write(path, bytes.NewReader(content), size)
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, size)
+
+				var buf bytes.Buffer
+				n, err = store.Read(path, &buf)
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, size)
+				So(sha256.Sum256(buf.Bytes()), ShouldResemble, sha256.Sum256(content))
+
+				err = store.Delete(path)
+				So(err, ShouldBeNil)
+
+				_, err = store.Stat(path)
+				So(errors.Is(err, services.ErrObjectNotExist), ShouldBeTrue)
+			})
+		})
+
+		Convey("When List storages", func() {
+			name := randUUID()
+			_, err := srv.Create(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				err := srv.Delete(name)
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+
+			it, err := srv.List()
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("The iterator should not be nil", func() {
+				So(it, ShouldNotBeNil)
+			})
+
+			Convey("The created storage should be listed", func() {
+				found := false
+				for {
+					store, err := it.Next()
+					if errors.Is(err, types.IterateDone) {
+						break
+					}
+					So(err, ShouldBeNil)
+
+					if store.Metadata().Name == name {
+						found = true
+					}
+				}
+				So(found, ShouldBeTrue)
+			})
+		})
+
+		Convey("When Delete a storage", func() {
+			name := randUUID()
+			_, err := srv.Create(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = srv.Delete(name)
+			Convey("The first returned error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			err = srv.Delete(name)
+			Convey("The second returned error also should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Get should not return a usable storage", func() {
+				store, err := srv.Get(name)
+				if err != nil {
+					// Service that checks existence in Get must return a ServiceError.
+					var se services.ServiceError
+					So(errors.As(err, &se), ShouldBeTrue)
+					So(se.Name, ShouldEqual, name)
+					return
+				}
+
+				// Service that doesn't check existence in Get must fail on the
+				// first operation with a StorageError.
+				_, err = store.Stat(randUUID())
+				So(err, ShouldNotBeNil)
+
+				var se services.StorageError
+				So(errors.As(err, &se), ShouldBeTrue)
+			})
+		})
+	})
+}
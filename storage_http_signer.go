@@ -6,33 +6,32 @@ import (
 	"errors"
 	"io"
 	"io/ioutil"
-	"math/rand"
 	"net/http"
 	"testing"
 	"time"
 
-	"github.com/google/uuid"
 	. "github.com/smartystreets/goconvey/convey"
 
 	"github.com/beyondstorage/go-storage/v4/pairs"
-	"github.com/beyondstorage/go-storage/v4/pkg/randbytes"
 	"github.com/beyondstorage/go-storage/v4/services"
 	"github.com/beyondstorage/go-storage/v4/types"
 )
 
 func TestStorageHTTPSignerRead(t *testing.T, store types.Storager) {
+	setupRand(t)
+
 	Convey("Given a basic Storager", t, func() {
 		signer, ok := store.(types.StorageHTTPSigner)
 		So(ok, ShouldBeTrue)
 
 		Convey("When Read via QuerySignHTTPRead", func() {
-			size := rand.Int63n(4 * 1024 * 1024)
-			content, err := ioutil.ReadAll(io.LimitReader(randbytes.NewRand(), size))
+			size := randInt63n(4 * 1024 * 1024)
+			content, err := ioutil.ReadAll(io.LimitReader(randReader(), size))
 			if err != nil {
 				t.Error(err)
 			}
 
-			path := uuid.New().String()
+			path := randUUID()
 			_, err = store.Write(path, bytes.NewReader(content), size)
 			if err != nil {
 				t.Error(err)
@@ -75,18 +74,20 @@ func TestStorageHTTPSignerRead(t *testing.T, store types.Storager) {
 }
 
 func TestStorageHTTPSignerWrite(t *testing.T, store types.Storager) {
+	setupRand(t)
+
 	Convey("Given a basic Storager", t, func() {
 		signer, ok := store.(types.StorageHTTPSigner)
 		So(ok, ShouldBeTrue)
 
 		Convey("When Write via QuerySignHTTPWrite", func() {
-			size := rand.Int63n(4 * 1024 * 1024)
-			content, err := ioutil.ReadAll(io.LimitReader(randbytes.NewRand(), size))
+			size := randInt63n(4 * 1024 * 1024)
+			content, err := ioutil.ReadAll(io.LimitReader(randReader(), size))
 			if err != nil {
 				t.Error(err)
 			}
 
-			path := uuid.New().String()
+			path := randUUID()
 			req, err := signer.QuerySignHTTPWrite(path, size, time.Duration(time.Hour))
 
 			Convey("The error should be nil", func() {
@@ -127,15 +128,17 @@ func TestStorageHTTPSignerWrite(t *testing.T, store types.Storager) {
 }
 
 func TestStorageHTTPSignerDelete(t *testing.T, store types.Storager) {
+	setupRand(t)
+
 	Convey("Given a basic Storager", t, func() {
 		signer, ok := store.(types.StorageHTTPSigner)
 		So(ok, ShouldBeTrue)
 
 		Convey("When Delete via QuerySignHTTPDelete", func() {
-			size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			r := io.LimitReader(randbytes.NewRand(), size)
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			r := io.LimitReader(randReader(), size)
 
-			path := uuid.New().String()
+			path := randUUID()
 			_, err := store.Write(path, r, size)
 			if err != nil {
 				t.Error(err)
@@ -169,7 +172,7 @@ func TestStorageHTTPSignerDelete(t *testing.T, store types.Storager) {
 			mu, ok := store.(types.Multiparter)
 			So(ok, ShouldBeTrue)
 
-			path := uuid.New().String()
+			path := randUUID()
 			o, err := mu.CreateMultipart(path)
 			if err != nil {
 				t.Error(err)
@@ -6,21 +6,24 @@ import (
 	"errors"
 	"io"
 	"io/ioutil"
-	"math/rand"
 	"path/filepath"
 	"strings"
 	"testing"
 
-	"github.com/google/uuid"
 	. "github.com/smartystreets/goconvey/convey"
 
 	ps "github.com/beyondstorage/go-storage/v4/pairs"
-	"github.com/beyondstorage/go-storage/v4/pkg/randbytes"
 	"github.com/beyondstorage/go-storage/v4/services"
 	"github.com/beyondstorage/go-storage/v4/types"
 )
 
 func TestStorager(t *testing.T, store types.Storager) {
+	TestStoragerWithProfile(t, store, Profile{})
+}
+
+func TestStoragerWithProfile(t *testing.T, store types.Storager, p Profile) {
+	setupRand(t)
+
 	Convey("Given a basic Storager", t, func() {
 		So(store, ShouldNotBeNil)
 
@@ -43,13 +46,13 @@ func TestStorager(t *testing.T, store types.Storager) {
 		workDir := store.Metadata().WorkDir
 
 		Convey("When Read a file", func() {
-			size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			content, err := ioutil.ReadAll(io.LimitReader(randbytes.NewRand(), size))
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			content, err := ioutil.ReadAll(io.LimitReader(randReader(), size))
 			if err != nil {
 				t.Error(err)
 			}
 
-			path := uuid.New().String()
+			path := randUUID()
 			_, err = store.Write(path, bytes.NewReader(content), size)
 			if err != nil {
 				t.Error(err)
@@ -78,13 +81,13 @@ func TestStorager(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When Read a file with offset or size", func() {
-			size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			content, err := ioutil.ReadAll(io.LimitReader(randbytes.NewRand(), size))
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			content, err := ioutil.ReadAll(io.LimitReader(randReader(), size))
 			if err != nil {
 				t.Error(err)
 			}
 
-			path := uuid.New().String()
+			path := randUUID()
 			_, err = store.Write(path, bytes.NewReader(content), size)
 			if err != nil {
 				t.Error(err)
@@ -97,7 +100,7 @@ func TestStorager(t *testing.T, store types.Storager) {
 			}()
 
 			Convey("When Read with offset", func() {
-				offset := rand.Int63n(size)
+				offset := randInt63n(size)
 
 				var buf bytes.Buffer
 				n, err := store.Read(path, &buf, ps.WithOffset(offset))
@@ -115,7 +118,7 @@ func TestStorager(t *testing.T, store types.Storager) {
 			})
 
 			Convey("When Read with size", func() {
-				len := rand.Int63n(size)
+				len := randInt63n(size)
 
 				var buf bytes.Buffer
 				n, err := store.Read(path, &buf, ps.WithSize(len))
@@ -133,8 +136,8 @@ func TestStorager(t *testing.T, store types.Storager) {
 			})
 
 			Convey("When Read with offset and size", func() {
-				offset := rand.Int63n(size)
-				len := rand.Int63n(size - offset)
+				offset := randInt63n(size)
+				len := randInt63n(size - offset)
 
 				var buf bytes.Buffer
 				n, err := store.Read(path, &buf, ps.WithOffset(offset), ps.WithSize(len))
@@ -153,9 +156,9 @@ func TestStorager(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When Write a file", func() {
-			firstSize := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			r := io.LimitReader(randbytes.NewRand(), firstSize)
-			path := uuid.New().String()
+			firstSize := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			r := io.LimitReader(randReader(), firstSize)
+			path := randUUID()
 
 			_, err := store.Write(path, r, firstSize)
 
@@ -170,8 +173,8 @@ func TestStorager(t *testing.T, store types.Storager) {
 				So(err, ShouldBeNil)
 			})
 
-			secondSize := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			content, _ := ioutil.ReadAll(io.LimitReader(randbytes.NewRand(), secondSize))
+			secondSize := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			content, _ := ioutil.ReadAll(io.LimitReader(randReader(), secondSize))
 
 			_, err = store.Write(path, bytes.NewReader(content), secondSize)
 
@@ -214,13 +217,13 @@ func TestStorager(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When Write and Read a file with IoCallback", func() {
-			size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			content, err := ioutil.ReadAll(io.LimitReader(randbytes.NewRand(), size))
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			content, err := ioutil.ReadAll(io.LimitReader(randReader(), size))
 			if err != nil {
 				t.Error(err)
 			}
 
-			path := uuid.New().String()
+			path := randUUID()
 
 			curWrite := int64(0)
 			writeFn := func(bs []byte) {
@@ -265,44 +268,46 @@ func TestStorager(t *testing.T, store types.Storager) {
 			})
 		})
 
-		Convey("When write a file with a nil io.Reader and 0 size", func() {
-			path := uuid.New().String()
-			var size int64 = 0
+		if !p.NoEmptyObjects {
+			Convey("When write a file with a nil io.Reader and 0 size", func() {
+				path := randUUID()
+				var size int64 = 0
 
-			_, err := store.Write(path, nil, size)
+				_, err := store.Write(path, nil, size)
 
-			defer func() {
-				err := store.Delete(path)
-				if err != nil {
-					t.Error(err)
-				}
-			}()
-
-			Convey("The error should be nil", func() {
-				So(err, ShouldBeNil)
-			})
-
-			Convey("Stat should get Object without error", func() {
-				o, err := store.Stat(path)
+				defer func() {
+					err := store.Delete(path)
+					if err != nil {
+						t.Error(err)
+					}
+				}()
 
 				Convey("The error should be nil", func() {
 					So(err, ShouldBeNil)
 				})
 
-				Convey("The name and size should be match", func() {
-					So(o, ShouldNotBeNil)
-					So(o.Path, ShouldEqual, path)
+				Convey("Stat should get Object without error", func() {
+					o, err := store.Stat(path)
 
-					osize, ok := o.GetContentLength()
-					So(ok, ShouldBeTrue)
-					So(osize, ShouldEqual, size)
+					Convey("The error should be nil", func() {
+						So(err, ShouldBeNil)
+					})
+
+					Convey("The name and size should be match", func() {
+						So(o, ShouldNotBeNil)
+						So(o.Path, ShouldEqual, path)
+
+						osize, ok := o.GetContentLength()
+						So(ok, ShouldBeTrue)
+						So(osize, ShouldEqual, size)
+					})
 				})
 			})
-		})
+		}
 
 		Convey("When write a file with a nil io.Reader and valid size", func() {
-			size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			path := uuid.New().String()
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			path := randUUID()
 
 			_, err := store.Write(path, nil, size)
 
@@ -318,48 +323,50 @@ func TestStorager(t *testing.T, store types.Storager) {
 			})
 		})
 
-		Convey("When write a file with a valid io.Reader and 0 size", func() {
-			var size int64 = 0
-			n := rand.Int63n(4 * 1024 * 1024)
-			r := io.LimitReader(randbytes.NewRand(), n)
-			path := uuid.New().String()
-
-			_, err := store.Write(path, r, size)
+		if !p.NoEmptyObjects {
+			Convey("When write a file with a valid io.Reader and 0 size", func() {
+				var size int64 = 0
+				n := randInt63n(4 * 1024 * 1024)
+				r := io.LimitReader(randReader(), n)
+				path := randUUID()
 
-			defer func() {
-				err := store.Delete(path)
-				if err != nil {
-					t.Error(err)
-				}
-			}()
+				_, err := store.Write(path, r, size)
 
-			Convey("The error should be nil", func() {
-				So(err, ShouldBeNil)
-			})
-
-			Convey("Stat should get Object without error", func() {
-				o, err := store.Stat(path)
+				defer func() {
+					err := store.Delete(path)
+					if err != nil {
+						t.Error(err)
+					}
+				}()
 
 				Convey("The error should be nil", func() {
 					So(err, ShouldBeNil)
 				})
 
-				Convey("The name and size should be match", func() {
-					So(o, ShouldNotBeNil)
-					So(o.Path, ShouldEqual, path)
+				Convey("Stat should get Object without error", func() {
+					o, err := store.Stat(path)
 
-					osize, ok := o.GetContentLength()
-					So(ok, ShouldBeTrue)
-					So(osize, ShouldEqual, size)
+					Convey("The error should be nil", func() {
+						So(err, ShouldBeNil)
+					})
+
+					Convey("The name and size should be match", func() {
+						So(o, ShouldNotBeNil)
+						So(o.Path, ShouldEqual, path)
+
+						osize, ok := o.GetContentLength()
+						So(ok, ShouldBeTrue)
+						So(osize, ShouldEqual, size)
+					})
 				})
 			})
-		})
+		}
 
 		Convey("When write a file with a valid io.Reader and length greater than size", func() {
-			n := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			size := rand.Int63n(n)
-			r, _ := ioutil.ReadAll(io.LimitReader(randbytes.NewRand(), n))
-			path := uuid.New().String()
+			n := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			size := randInt63n(n)
+			r, _ := ioutil.ReadAll(io.LimitReader(randReader(), n))
+			path := randUUID()
 
 			_, err := store.Write(path, bytes.NewReader(r), size)
 
@@ -410,13 +417,13 @@ func TestStorager(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When Stat a file", func() {
-			size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			content, err := ioutil.ReadAll(io.LimitReader(randbytes.NewRand(), size))
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			content, err := ioutil.ReadAll(io.LimitReader(randReader(), size))
 			if err != nil {
 				t.Error(err)
 			}
 
-			path := uuid.New().String()
+			path := randUUID()
 			_, err = store.Write(path, bytes.NewReader(content), size)
 			if err != nil {
 				t.Error(err)
@@ -445,13 +452,13 @@ func TestStorager(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When Delete a file", func() {
-			size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			content, err := ioutil.ReadAll(io.LimitReader(randbytes.NewRand(), size))
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			content, err := ioutil.ReadAll(io.LimitReader(randReader(), size))
 			if err != nil {
 				t.Error(err)
 			}
 
-			path := uuid.New().String()
+			path := randUUID()
 			_, err = store.Write(path, bytes.NewReader(content), size)
 			if err != nil {
 				t.Error(err)
@@ -498,9 +505,9 @@ func TestStorager(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When List a dir within files", func() {
-			size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			r := io.LimitReader(randbytes.NewRand(), size)
-			path := uuid.New().String()
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			r := io.LimitReader(randReader(), size)
+			path := randUUID()
 			_, err := store.Write(path, r, size)
 			if err != nil {
 				t.Error(err)
@@ -532,9 +539,9 @@ func TestStorager(t *testing.T, store types.Storager) {
 		})
 
 		Convey("When List without ListMode", func() {
-			size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-			r := io.LimitReader(randbytes.NewRand(), size)
-			path := uuid.New().String()
+			size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+			r := io.LimitReader(randReader(), size)
+			path := randUUID()
 			_, err := store.Write(path, r, size)
 			if err != nil {
 				t.Error(err)
@@ -567,13 +574,13 @@ func TestStorager(t *testing.T, store types.Storager) {
 
 		Convey("When testing GSP-749 unify path behavior", func() {
 			Convey("When using absolute path", func() {
-				size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-				content, err := ioutil.ReadAll(io.LimitReader(randbytes.NewRand(), size))
+				size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+				content, err := ioutil.ReadAll(io.LimitReader(randReader(), size))
 				if err != nil {
 					t.Error(err)
 				}
 
-				path := uuid.New().String()
+				path := randUUID()
 				absPath := filepath.Join(workDir, path)
 				_, err = store.Write(absPath, bytes.NewReader(content), size)
 				if err != nil {
@@ -614,13 +621,13 @@ func TestStorager(t *testing.T, store types.Storager) {
 			})
 
 			Convey("When using backslash in path", func() {
-				size := rand.Int63n(4 * 1024 * 1024) // Max file size is 4MB
-				content, err := ioutil.ReadAll(io.LimitReader(randbytes.NewRand(), size))
+				size := randInt63n(4 * 1024 * 1024) // Max file size is 4MB
+				content, err := ioutil.ReadAll(io.LimitReader(randReader(), size))
 				if err != nil {
 					t.Error(err)
 				}
 
-				path := uuid.New().String() + "\\" + uuid.New().String()
+				path := randUUID() + "\\" + randUUID()
 				_, err = store.Write(path, bytes.NewReader(content), size)
 				if err != nil {
 					t.Error(err)
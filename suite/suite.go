@@ -0,0 +1,64 @@
+// Package suite describes the suites run by RunAll and the interfaces they cover.
+//
+// It doesn't depend on testing and goconvey, so that tools like conformance-report
+// could use it without registering the test flags.
+package suite
+
+// Suite is a suite run by RunAll.
+type Suite struct {
+	Name string
+	// Requires is the names of interfaces in types that store must implement,
+	// the suite will be skipped if store doesn't implement all of them.
+	Requires []string
+}
+
+// Suites is all suites run by RunAll in order, after TestStorager which is not listed.
+var Suites = []Suite{
+	{Name: "Appender", Requires: []string{"Appender"}},
+	{Name: "Blocker", Requires: []string{"Blocker"}},
+	{Name: "ContextCancellation"},
+	{Name: "Copier", Requires: []string{"Copier"}},
+	{Name: "DefaultPairs"},
+	{Name: "Direr", Requires: []string{"Direr"}},
+	{Name: "Errors"},
+	{Name: "FaultInjection"},
+	{Name: "Fetcher", Requires: []string{"Fetcher"}},
+	{Name: "LargeObject"},
+	{Name: "Linker", Requires: []string{"Linker"}},
+	{Name: "ListHierarchy"},
+	{Name: "ListPagination"},
+	{Name: "Metadata"},
+	{Name: "Model"},
+	{Name: "Mover", Requires: []string{"Mover"}},
+	{Name: "Multiparter", Requires: []string{"Multiparter"}},
+	{Name: "Pager", Requires: []string{"Pager"}},
+	{Name: "PathEdgeCases"},
+	{Name: "Reacher", Requires: []string{"Reacher"}},
+	{Name: "ReadRange"},
+	{Name: "StoragerConcurrency"},
+	{Name: "UnsupportedPairs"},
+	{Name: "StorageHTTPSignerRead", Requires: []string{"StorageHTTPSigner"}},
+	{Name: "StorageHTTPSignerWrite", Requires: []string{"StorageHTTPSigner"}},
+	// TestStorageHTTPSignerDelete also deletes multipart objects via signed requests.
+	{Name: "StorageHTTPSignerDelete", Requires: []string{"StorageHTTPSigner", "Multiparter"}},
+	{Name: "MultipartHTTPSigner", Requires: []string{"MultipartHTTPSigner", "Multiparter"}},
+}
+
+// Interfaces returns the interfaces covered by the suite named name in RunAll,
+// nil will be returned if there is no such suite.
+func Interfaces(name string) []string {
+	if name == "Storager" {
+		return []string{"Storager"}
+	}
+	for _, s := range Suites {
+		if s.Name != name {
+			continue
+		}
+		// Suites which don't require any interface only cover Storager.
+		if len(s.Requires) == 0 {
+			return []string{"Storager"}
+		}
+		return append([]string(nil), s.Requires...)
+	}
+	return nil
+}